@@ -0,0 +1,360 @@
+// Package catalog loads Discogs data dumps into a normalized SQL database
+// and keeps individual rows fresh with API calls.
+//
+// The package uses database/sql and does not register a driver itself; the
+// statements target SQLite. Import a driver such as github.com/mattn/go-sqlite3:
+//
+//	db, err := sql.Open("sqlite3", "discogs.db")
+//	if err != nil {
+//		return err
+//	}
+//
+//	c, err := catalog.New(db, client)
+//	if err != nil {
+//		return err
+//	}
+//
+//	d, err := dump.Open("discogs_20200101_releases.xml.gz")
+//	if err != nil {
+//		return err
+//	}
+//	defer d.Close()
+//
+//	n, err := c.Load(ctx, d)
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ninnemana/go-discogs"
+	"github.com/ninnemana/go-discogs/dump"
+)
+
+// batchSize is the number of entities stored per transaction by Load.
+const batchSize = 1000
+
+// Catalog is a local copy of the Discogs database.
+type Catalog struct {
+	db     *sql.DB
+	client discogs.DatabaseService
+	now    func() time.Time
+}
+
+// New creates the catalog schema in db if needed and returns the catalog.
+// client is used to refresh rows; it may be nil if only dumps are loaded.
+func New(db *sql.DB, client discogs.DatabaseService) (*Catalog, error) {
+	for _, stmt := range schema {
+		if _, err := db.Exec(stmt); err != nil {
+			return nil, fmt.Errorf("catalog: failed to create schema: %w", err)
+		}
+	}
+
+	return &Catalog{
+		db:     db,
+		client: client,
+		now:    time.Now,
+	}, nil
+}
+
+// execer is implemented by *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// Load stores every entity decoded from d and returns the number of entities stored.
+func (c *Catalog) Load(ctx context.Context, d *dump.Decoder) (int, error) {
+	var (
+		n  int
+		tx *sql.Tx
+	)
+	for {
+		v, err := d.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if tx != nil {
+				tx.Rollback()
+			}
+			return n, err
+		}
+
+		if tx == nil {
+			if tx, err = c.db.BeginTx(ctx, nil); err != nil {
+				return n, err
+			}
+		}
+
+		if err := c.put(ctx, tx, v, sql.NullInt64{}); err != nil {
+			tx.Rollback()
+			return n, err
+		}
+		n++
+
+		if n%batchSize == 0 {
+			if err := tx.Commit(); err != nil {
+				return n, err
+			}
+			tx = nil
+		}
+	}
+
+	if tx != nil {
+		if err := tx.Commit(); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// PutRelease stores a release, replacing any previous version of it.
+func (c *Catalog) PutRelease(ctx context.Context, r *discogs.Release) error {
+	return c.inTx(ctx, func(tx *sql.Tx) error {
+		return c.putRelease(ctx, tx, r, sql.NullInt64{})
+	})
+}
+
+// PutArtist stores an artist, replacing any previous version of it.
+func (c *Catalog) PutArtist(ctx context.Context, a *discogs.Artist) error {
+	return c.inTx(ctx, func(tx *sql.Tx) error {
+		return c.putArtist(ctx, tx, a, sql.NullInt64{})
+	})
+}
+
+// PutLabel stores a label, replacing any previous version of it.
+func (c *Catalog) PutLabel(ctx context.Context, l *discogs.Label) error {
+	return c.inTx(ctx, func(tx *sql.Tx) error {
+		return c.putLabel(ctx, tx, l, sql.NullInt64{})
+	})
+}
+
+// PutMaster stores a master release, replacing any previous version of it.
+func (c *Catalog) PutMaster(ctx context.Context, m *discogs.Master) error {
+	return c.inTx(ctx, func(tx *sql.Tx) error {
+		return c.putMaster(ctx, tx, m, sql.NullInt64{})
+	})
+}
+
+func (c *Catalog) inTx(ctx context.Context, f func(tx *sql.Tx) error) error {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := f(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (c *Catalog) put(ctx context.Context, e execer, v interface{}, refreshed sql.NullInt64) error {
+	switch t := v.(type) {
+	case *discogs.Release:
+		return c.putRelease(ctx, e, t, refreshed)
+	case *discogs.Artist:
+		return c.putArtist(ctx, e, t, refreshed)
+	case *discogs.Label:
+		return c.putLabel(ctx, e, t, refreshed)
+	case *discogs.Master:
+		return c.putMaster(ctx, e, t, refreshed)
+	default:
+		return fmt.Errorf("catalog: unsupported entity %T", v)
+	}
+}
+
+func (c *Catalog) putRelease(ctx context.Context, e execer, r *discogs.Release, refreshed sql.NullInt64) error {
+	for _, table := range releaseChildren {
+		if _, err := e.ExecContext(ctx, "DELETE FROM "+table+" WHERE release_id = ?", r.ID); err != nil {
+			return err
+		}
+	}
+
+	if _, err := e.ExecContext(ctx,
+		`INSERT OR REPLACE INTO releases (id, master_id, title, country, released, year, status, notes, data_quality, date_changed, refreshed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		r.ID, r.MasterID, r.Title, r.Country, r.Released, r.Year, r.Status, r.Notes, r.DataQuality, r.DateChanged, refreshed,
+	); err != nil {
+		return err
+	}
+
+	for _, g := range r.Genres {
+		if _, err := e.ExecContext(ctx, `INSERT OR IGNORE INTO release_genres (release_id, genre) VALUES (?, ?)`, r.ID, g); err != nil {
+			return err
+		}
+	}
+	for _, st := range r.Styles {
+		if _, err := e.ExecContext(ctx, `INSERT OR IGNORE INTO release_styles (release_id, style) VALUES (?, ?)`, r.ID, st); err != nil {
+			return err
+		}
+	}
+
+	credit := func(trackSeq sql.NullInt64, seq int, a discogs.ArtistSource, extra bool) error {
+		_, err := e.ExecContext(ctx,
+			`INSERT INTO credits (release_id, track_seq, seq, artist_id, name, anv, join_string, role, tracks, extra)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			r.ID, trackSeq, seq, a.ID, a.Name, a.Anv, a.Join, a.Role, a.Tracks, extra,
+		)
+		return err
+	}
+
+	for i, a := range r.Artists {
+		if err := credit(sql.NullInt64{}, i, a, false); err != nil {
+			return err
+		}
+	}
+	for i, a := range r.ExtraArtists {
+		if err := credit(sql.NullInt64{}, i, a, true); err != nil {
+			return err
+		}
+	}
+
+	// sub tracks are stored as rows after their index track
+	for i, t := range flatten(r.Tracklist) {
+		if _, err := e.ExecContext(ctx,
+			`INSERT INTO tracks (release_id, seq, position, type, title, duration) VALUES (?, ?, ?, ?, ?, ?)`,
+			r.ID, i, t.Position, t.Type, t.Title, t.Duration,
+		); err != nil {
+			return err
+		}
+
+		seq := sql.NullInt64{Int64: int64(i), Valid: true}
+		for j, a := range t.Artists {
+			if err := credit(seq, j, a, false); err != nil {
+				return err
+			}
+		}
+		for j, a := range t.Extraartists {
+			if err := credit(seq, j, a, true); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, l := range r.Labels {
+		if _, err := e.ExecContext(ctx,
+			`INSERT INTO release_labels (release_id, seq, label_id, name, catno) VALUES (?, ?, ?, ?, ?)`,
+			r.ID, i, l.ID, l.Name, l.Catno,
+		); err != nil {
+			return err
+		}
+	}
+
+	for i, id := range r.Identifiers {
+		if _, err := e.ExecContext(ctx,
+			`INSERT INTO identifiers (release_id, seq, type, value, description) VALUES (?, ?, ?, ?, ?)`,
+			r.ID, i, id.Type, id.Value, id.Description,
+		); err != nil {
+			return err
+		}
+	}
+
+	for i, f := range r.Formats {
+		if _, err := e.ExecContext(ctx,
+			`INSERT INTO formats (release_id, seq, name, qty) VALUES (?, ?, ?, ?)`,
+			r.ID, i, f.Name, f.Qty,
+		); err != nil {
+			return err
+		}
+		for j, d := range f.Descriptions {
+			if _, err := e.ExecContext(ctx,
+				`INSERT INTO format_descriptions (release_id, format_seq, seq, description) VALUES (?, ?, ?, ?)`,
+				r.ID, i, j, d,
+			); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *Catalog) putArtist(ctx context.Context, e execer, a *discogs.Artist, refreshed sql.NullInt64) error {
+	if _, err := e.ExecContext(ctx,
+		`INSERT OR REPLACE INTO artists (id, name, realname, profile, data_quality, refreshed_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		a.ID, a.Name, a.Realname, a.Profile, a.DataQuality, refreshed,
+	); err != nil {
+		return err
+	}
+
+	for _, table := range []string{"artist_aliases", "artist_members"} {
+		if _, err := e.ExecContext(ctx, "DELETE FROM "+table+" WHERE artist_id = ?", a.ID); err != nil {
+			return err
+		}
+	}
+	for _, al := range a.Aliases {
+		if _, err := e.ExecContext(ctx,
+			`INSERT OR REPLACE INTO artist_aliases (artist_id, alias_id, name) VALUES (?, ?, ?)`,
+			a.ID, al.ID, al.Name,
+		); err != nil {
+			return err
+		}
+	}
+	for _, m := range a.Members {
+		if _, err := e.ExecContext(ctx,
+			`INSERT OR REPLACE INTO artist_members (artist_id, member_id, name, active) VALUES (?, ?, ?, ?)`,
+			a.ID, m.ID, m.Name, m.Active,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Catalog) putLabel(ctx context.Context, e execer, l *discogs.Label, refreshed sql.NullInt64) error {
+	if _, err := e.ExecContext(ctx,
+		`INSERT OR REPLACE INTO labels (id, name, contact_info, profile, data_quality, refreshed_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		l.ID, l.Name, l.ContactInfo, l.Profile, l.DataQuality, refreshed,
+	); err != nil {
+		return err
+	}
+
+	if _, err := e.ExecContext(ctx, `DELETE FROM sublabels WHERE label_id = ?`, l.ID); err != nil {
+		return err
+	}
+	for _, s := range l.Sublabels {
+		if _, err := e.ExecContext(ctx,
+			`INSERT OR REPLACE INTO sublabels (label_id, sublabel_id, name) VALUES (?, ?, ?)`,
+			l.ID, s.ID, s.Name,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Catalog) putMaster(ctx context.Context, e execer, m *discogs.Master, refreshed sql.NullInt64) error {
+	if _, err := e.ExecContext(ctx,
+		`INSERT OR REPLACE INTO masters (id, title, year, main_release, data_quality, refreshed_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		m.ID, m.Title, m.Year, m.MainRelease, m.DataQuality, refreshed,
+	); err != nil {
+		return err
+	}
+
+	if _, err := e.ExecContext(ctx, `DELETE FROM master_artists WHERE master_id = ?`, m.ID); err != nil {
+		return err
+	}
+	for i, a := range m.Artists {
+		if _, err := e.ExecContext(ctx,
+			`INSERT INTO master_artists (master_id, seq, artist_id, name, anv, join_string) VALUES (?, ?, ?, ?, ?, ?)`,
+			m.ID, i, a.ID, a.Name, a.Anv, a.Join,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flatten lists the tracks of a tracklist with the sub tracks of an index
+// track following it.
+func flatten(tracks []discogs.Track) []discogs.Track {
+	var out []discogs.Track
+	for _, t := range tracks {
+		out = append(out, t)
+		out = append(out, flatten(t.SubTracks)...)
+	}
+	return out
+}
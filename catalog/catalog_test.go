@@ -0,0 +1,148 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/ninnemana/go-discogs"
+	"github.com/ninnemana/go-discogs/discogstest"
+	"github.com/ninnemana/go-discogs/dump"
+)
+
+func openCatalog(t *testing.T, client discogs.DatabaseService) (*Catalog, *sql.DB) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database: %s", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	c, err := New(db, client)
+	if err != nil {
+		t.Fatalf("failed to create catalog: %s", err)
+	}
+	return c, db
+}
+
+func count(t *testing.T, db *sql.DB, query string, args ...interface{}) int {
+	var n int
+	if err := db.QueryRow(query, args...).Scan(&n); err != nil {
+		t.Fatalf("failed to query %q: %s", query, err)
+	}
+	return n
+}
+
+func TestCatalogLoad(t *testing.T) {
+	c, db := openCatalog(t, nil)
+	defer db.Close()
+
+	for _, name := range []string{"artists.xml", "labels.xml", "masters.xml", "releases.xml.gz"} {
+		d, err := dump.Open("../dump/testdata/" + name)
+		if err != nil {
+			t.Fatalf("failed to open dump: %s", err)
+		}
+		if _, err := c.Load(context.Background(), d); err != nil {
+			t.Fatalf("failed to load %s: %s", name, err)
+		}
+		d.Close()
+	}
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{`SELECT COUNT(*) FROM artists`, 2},
+		{`SELECT COUNT(*) FROM artist_members WHERE artist_id = 2`, 2},
+		{`SELECT COUNT(*) FROM labels`, 2},
+		{`SELECT COUNT(*) FROM sublabels WHERE label_id = 1`, 2},
+		{`SELECT COUNT(*) FROM masters`, 1},
+		{`SELECT COUNT(*) FROM releases`, 2},
+		{`SELECT COUNT(*) FROM tracks WHERE release_id = 1`, 4},
+		{`SELECT COUNT(*) FROM credits WHERE release_id = 1 AND track_seq IS NOT NULL`, 1},
+		{`SELECT COUNT(*) FROM credits WHERE artist_id = 2`, 2},
+		{`SELECT COUNT(*) FROM identifiers WHERE type = 'Barcode'`, 1},
+		{`SELECT COUNT(*) FROM format_descriptions WHERE release_id = 1`, 2},
+		{`SELECT COUNT(*) FROM release_labels WHERE label_id = 5`, 2},
+	}
+	for _, tt := range tests {
+		if got := count(t, db, tt.query); got != tt.want {
+			t.Errorf("%s got=%d; want=%d", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestCatalogSubTracks(t *testing.T) {
+	c, db := openCatalog(t, nil)
+	defer db.Close()
+
+	if err := c.PutRelease(context.Background(), &discogs.Release{ID: 1, Title: "Suite", Tracklist: []discogs.Track{
+		{Position: "A1", Title: "Intro", Type: discogs.TrackTypeTrack},
+		{Position: "A2", Title: "Suite", Type: discogs.TrackTypeIndex, SubTracks: []discogs.Track{
+			{Position: "A2a", Title: "Part One", Type: discogs.TrackTypeTrack},
+			{Position: "A2b", Title: "Part Two", Type: discogs.TrackTypeTrack},
+		}},
+	}}); err != nil {
+		t.Fatalf("failed to put release: %s", err)
+	}
+	if got := count(t, db, `SELECT COUNT(*) FROM tracks WHERE release_id = 1`); got != 4 {
+		t.Errorf("tracks got=%d; want=4", got)
+	}
+	if got := count(t, db, `SELECT seq FROM tracks WHERE release_id = 1 AND position = 'A2b'`); got != 3 {
+		t.Errorf("sub track seq got=%d; want=3", got)
+	}
+}
+
+func TestCatalogRefresh(t *testing.T) {
+	s := discogstest.NewServer()
+	defer s.Close()
+
+	s.AddRelease(discogs.Release{
+		ID:          1,
+		Title:       "Stockholm",
+		DateChanged: "2020-01-01T00:00:00-08:00",
+		Tracklist:   []discogs.Track{{Position: "A", Title: "Östermalm"}},
+	})
+
+	client, err := discogs.New(s.Options())
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+
+	c, db := openCatalog(t, client)
+	defer db.Close()
+
+	now := time.Unix(1600000000, 0)
+	c.now = func() time.Time { return now }
+
+	if err := c.PutRelease(context.Background(), &discogs.Release{ID: 1, Title: "Stockholm (dump)"}); err != nil {
+		t.Fatalf("failed to put release: %s", err)
+	}
+
+	n, err := c.RefreshStaleReleases(context.Background(), time.Hour, 10)
+	if err != nil {
+		t.Fatalf("failed to refresh: %s", err)
+	}
+	if n != 1 {
+		t.Errorf("changed got=%d; want=1", n)
+	}
+	if got := count(t, db, `SELECT COUNT(*) FROM releases WHERE title = 'Stockholm' AND refreshed_at = ?`, now.Unix()); got != 1 {
+		t.Errorf("refreshed releases got=%d; want=1", got)
+	}
+	if got := count(t, db, `SELECT COUNT(*) FROM tracks WHERE release_id = 1`); got != 1 {
+		t.Errorf("tracks got=%d; want=1", got)
+	}
+
+	changed, err := c.RefreshRelease(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("failed to refresh: %s", err)
+	}
+	if changed {
+		t.Errorf("unchanged release was replaced")
+	}
+
+	if n, err := c.RefreshStaleReleases(context.Background(), time.Hour, 10); err != nil || n != 0 {
+		t.Errorf("stale refresh got=%d, %v; want=0", n, err)
+	}
+}
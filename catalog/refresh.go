@@ -0,0 +1,133 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// ErrNoClient is returned when refreshing a catalog created without an API client.
+var ErrNoClient = errors.New("catalog: no API client")
+
+// RefreshRelease fetches a release from the API and stores it if its
+// date_changed differs from the stored row, or if it is not stored yet.
+// It reports whether the row was replaced.
+func (c *Catalog) RefreshRelease(ctx context.Context, releaseID int) (bool, error) {
+	if c.client == nil {
+		return false, ErrNoClient
+	}
+
+	release, err := c.client.Release(releaseID)
+	if err != nil {
+		return false, err
+	}
+
+	refreshed := sql.NullInt64{Int64: c.now().Unix(), Valid: true}
+
+	var changed sql.NullString
+	err = c.db.QueryRowContext(ctx, `SELECT date_changed FROM releases WHERE id = ?`, releaseID).Scan(&changed)
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return false, err
+	case changed.Valid && changed.String != "" && changed.String == release.DateChanged:
+		_, err := c.db.ExecContext(ctx, `UPDATE releases SET refreshed_at = ? WHERE id = ?`, refreshed, releaseID)
+		return false, err
+	}
+
+	return true, c.inTx(ctx, func(tx *sql.Tx) error {
+		return c.putRelease(ctx, tx, release, refreshed)
+	})
+}
+
+// RefreshArtist fetches an artist from the API and stores it.
+func (c *Catalog) RefreshArtist(ctx context.Context, artistID int) error {
+	if c.client == nil {
+		return ErrNoClient
+	}
+
+	artist, err := c.client.Artist(artistID)
+	if err != nil {
+		return err
+	}
+
+	return c.inTx(ctx, func(tx *sql.Tx) error {
+		return c.putArtist(ctx, tx, artist, sql.NullInt64{Int64: c.now().Unix(), Valid: true})
+	})
+}
+
+// RefreshLabel fetches a label from the API and stores it.
+func (c *Catalog) RefreshLabel(ctx context.Context, labelID int) error {
+	if c.client == nil {
+		return ErrNoClient
+	}
+
+	label, err := c.client.Label(labelID)
+	if err != nil {
+		return err
+	}
+
+	return c.inTx(ctx, func(tx *sql.Tx) error {
+		return c.putLabel(ctx, tx, label, sql.NullInt64{Int64: c.now().Unix(), Valid: true})
+	})
+}
+
+// RefreshMaster fetches a master release from the API and stores it.
+func (c *Catalog) RefreshMaster(ctx context.Context, masterID int) error {
+	if c.client == nil {
+		return ErrNoClient
+	}
+
+	master, err := c.client.Master(masterID)
+	if err != nil {
+		return err
+	}
+
+	return c.inTx(ctx, func(tx *sql.Tx) error {
+		return c.putMaster(ctx, tx, master, sql.NullInt64{Int64: c.now().Unix(), Valid: true})
+	})
+}
+
+// RefreshStaleReleases refreshes up to limit releases that were never refreshed
+// or were last refreshed more than maxAge ago, least recently refreshed first.
+// It returns the number of releases that had changed.
+func (c *Catalog) RefreshStaleReleases(ctx context.Context, maxAge time.Duration, limit int) (int, error) {
+	if c.client == nil {
+		return 0, ErrNoClient
+	}
+
+	rows, err := c.db.QueryContext(ctx,
+		`SELECT id FROM releases WHERE refreshed_at IS NULL OR refreshed_at < ? ORDER BY refreshed_at, id LIMIT ?`,
+		c.now().Add(-maxAge).Unix(), limit,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var n int
+	for _, id := range ids {
+		changed, err := c.RefreshRelease(ctx, id)
+		if err != nil {
+			return n, err
+		}
+		if changed {
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,152 @@
+package catalog
+
+// schema is the normalized layout of the catalog. Child rows reference their
+// parent by id and are ordered by seq, the index in the source slice.
+// refreshed_at is the unix time of the last API refresh, NULL for rows loaded
+// from a dump only.
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS artists (
+		id INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		realname TEXT,
+		profile TEXT,
+		data_quality TEXT,
+		refreshed_at INTEGER
+	)`,
+	`CREATE TABLE IF NOT EXISTS artist_aliases (
+		artist_id INTEGER NOT NULL REFERENCES artists(id),
+		alias_id INTEGER NOT NULL,
+		name TEXT,
+		PRIMARY KEY (artist_id, alias_id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS artist_members (
+		artist_id INTEGER NOT NULL REFERENCES artists(id),
+		member_id INTEGER NOT NULL,
+		name TEXT,
+		active BOOLEAN,
+		PRIMARY KEY (artist_id, member_id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS labels (
+		id INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		contact_info TEXT,
+		profile TEXT,
+		data_quality TEXT,
+		refreshed_at INTEGER
+	)`,
+	`CREATE TABLE IF NOT EXISTS sublabels (
+		label_id INTEGER NOT NULL REFERENCES labels(id),
+		sublabel_id INTEGER NOT NULL,
+		name TEXT,
+		PRIMARY KEY (label_id, sublabel_id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS masters (
+		id INTEGER PRIMARY KEY,
+		title TEXT NOT NULL,
+		year INTEGER,
+		main_release INTEGER,
+		data_quality TEXT,
+		refreshed_at INTEGER
+	)`,
+	`CREATE TABLE IF NOT EXISTS master_artists (
+		master_id INTEGER NOT NULL REFERENCES masters(id),
+		seq INTEGER NOT NULL,
+		artist_id INTEGER,
+		name TEXT,
+		anv TEXT,
+		join_string TEXT,
+		PRIMARY KEY (master_id, seq)
+	)`,
+	`CREATE TABLE IF NOT EXISTS releases (
+		id INTEGER PRIMARY KEY,
+		master_id INTEGER,
+		title TEXT NOT NULL,
+		country TEXT,
+		released TEXT,
+		year INTEGER,
+		status TEXT,
+		notes TEXT,
+		data_quality TEXT,
+		date_changed TEXT,
+		refreshed_at INTEGER
+	)`,
+	`CREATE INDEX IF NOT EXISTS releases_master_id ON releases (master_id)`,
+	`CREATE TABLE IF NOT EXISTS release_genres (
+		release_id INTEGER NOT NULL REFERENCES releases(id),
+		genre TEXT NOT NULL,
+		PRIMARY KEY (release_id, genre)
+	)`,
+	`CREATE TABLE IF NOT EXISTS release_styles (
+		release_id INTEGER NOT NULL REFERENCES releases(id),
+		style TEXT NOT NULL,
+		PRIMARY KEY (release_id, style)
+	)`,
+	`CREATE TABLE IF NOT EXISTS tracks (
+		release_id INTEGER NOT NULL REFERENCES releases(id),
+		seq INTEGER NOT NULL,
+		position TEXT,
+		type TEXT,
+		title TEXT,
+		duration TEXT,
+		PRIMARY KEY (release_id, seq)
+	)`,
+	`CREATE TABLE IF NOT EXISTS credits (
+		release_id INTEGER NOT NULL REFERENCES releases(id),
+		track_seq INTEGER,
+		seq INTEGER NOT NULL,
+		artist_id INTEGER,
+		name TEXT,
+		anv TEXT,
+		join_string TEXT,
+		role TEXT,
+		tracks TEXT,
+		extra BOOLEAN NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS credits_release_id ON credits (release_id)`,
+	`CREATE INDEX IF NOT EXISTS credits_artist_id ON credits (artist_id)`,
+	`CREATE TABLE IF NOT EXISTS release_labels (
+		release_id INTEGER NOT NULL REFERENCES releases(id),
+		seq INTEGER NOT NULL,
+		label_id INTEGER,
+		name TEXT,
+		catno TEXT,
+		PRIMARY KEY (release_id, seq)
+	)`,
+	`CREATE INDEX IF NOT EXISTS release_labels_label_id ON release_labels (label_id)`,
+	`CREATE TABLE IF NOT EXISTS identifiers (
+		release_id INTEGER NOT NULL REFERENCES releases(id),
+		seq INTEGER NOT NULL,
+		type TEXT,
+		value TEXT,
+		description TEXT,
+		PRIMARY KEY (release_id, seq)
+	)`,
+	`CREATE INDEX IF NOT EXISTS identifiers_value ON identifiers (value)`,
+	`CREATE TABLE IF NOT EXISTS formats (
+		release_id INTEGER NOT NULL REFERENCES releases(id),
+		seq INTEGER NOT NULL,
+		name TEXT,
+		qty TEXT,
+		PRIMARY KEY (release_id, seq)
+	)`,
+	`CREATE TABLE IF NOT EXISTS format_descriptions (
+		release_id INTEGER NOT NULL REFERENCES releases(id),
+		format_seq INTEGER NOT NULL,
+		seq INTEGER NOT NULL,
+		description TEXT,
+		PRIMARY KEY (release_id, format_seq, seq)
+	)`,
+}
+
+// releaseChildren lists the tables holding rows owned by a release, which are
+// replaced as a whole whenever the release is stored.
+var releaseChildren = []string{
+	"release_genres",
+	"release_styles",
+	"tracks",
+	"credits",
+	"release_labels",
+	"identifiers",
+	"formats",
+	"format_descriptions",
+}
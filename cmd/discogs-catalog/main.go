@@ -0,0 +1,92 @@
+// Command discogs-catalog loads Discogs data dumps into a SQLite database
+// and refreshes stale releases through the API.
+//
+// Usage:
+//
+//	discogs-catalog -db discogs.db discogs_20200101_artists.xml.gz discogs_20200101_releases.xml.gz
+//	discogs-catalog -db discogs.db -refresh 500 -max-age 720h
+//
+// The API token and user agent are read from DISCOGS_TOKEN and DISCOGS_USER_AGENT.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/ninnemana/go-discogs"
+	"github.com/ninnemana/go-discogs/catalog"
+	"github.com/ninnemana/go-discogs/dump"
+)
+
+func main() {
+	var (
+		path    = flag.String("db", "discogs.db", "SQLite database file")
+		refresh = flag.Int("refresh", 0, "number of stale releases to refresh through the API")
+		maxAge  = flag.Duration("max-age", 30*24*time.Hour, "age after which a release is stale")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] [dump files]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := run(context.Background(), *path, flag.Args(), *refresh, *maxAge); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, path string, dumps []string, refresh int, maxAge time.Duration) error {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var client discogs.Discogs
+	if refresh > 0 {
+		userAgent := os.Getenv("DISCOGS_USER_AGENT")
+		if userAgent == "" {
+			userAgent = "discogs-catalog"
+		}
+		if client, err = discogs.New(&discogs.Options{
+			UserAgent: userAgent,
+			Token:     os.Getenv("DISCOGS_TOKEN"),
+		}); err != nil {
+			return err
+		}
+	}
+
+	c, err := catalog.New(db, client)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range dumps {
+		d, err := dump.Open(name)
+		if err != nil {
+			return err
+		}
+
+		n, err := c.Load(ctx, d)
+		d.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		log.Printf("%s: loaded %d entities", name, n)
+	}
+
+	if refresh > 0 {
+		n, err := c.RefreshStaleReleases(ctx, maxAge, refresh)
+		if err != nil {
+			return err
+		}
+		log.Printf("refreshed releases: %d changed", n)
+	}
+	return nil
+}
@@ -0,0 +1,526 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/gomodule/oauth1/oauth"
+	"github.com/ninnemana/go-discogs"
+	"github.com/ninnemana/go-discogs/export"
+	"github.com/ninnemana/go-discogs/importer"
+)
+
+// cli is the state shared by the commands.
+type cli struct {
+	conf   *config
+	stdout io.Writer
+	stderr io.Writer
+	client discogs.Discogs
+}
+
+type command struct {
+	usage string
+	run   func(c *cli, args []string) error
+}
+
+var commands = map[string]command{
+	"release":    {"<id>", releaseCmd},
+	"master":     {"<id> [--versions [--format f] [--label l] [--released year] [--country c]] [--page n] [--per-page n]", masterCmd},
+	"artist":     {"<id> [--releases] [--page n] [--per-page n]", artistCmd},
+	"label":      {"<id> [--releases] [--page n] [--per-page n]", labelCmd},
+	"search":     {"[--q query] [--type type] [--artist name] [--year year|from-to] [--format format] ... | --url url", searchCmd},
+	"collection": {"folders <username> | export <username> [--format csv|jsonl|flat] [--resolve] | import <username> <file> [--apply] [--resume report.json]", collectionCmd},
+	"identity":   {"show the user authenticated with OAuth", identityCmd},
+}
+
+// discogs returns the API client, creating it on first use.
+func (c *cli) discogs() (discogs.Discogs, error) {
+	if c.client != nil {
+		return c.client, nil
+	}
+
+	client, err := discogs.New(&discogs.Options{
+		URL:       c.conf.URL,
+		Currency:  c.conf.Currency,
+		UserAgent: c.conf.UserAgent,
+		Token:     c.conf.Token,
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.client = client
+	return client, nil
+}
+
+// oauth returns the options authenticating requests with the configured OAuth credentials.
+func (c *cli) oauth() ([]discogs.Option, error) {
+	if c.conf.ConsumerKey == "" || c.conf.OAuthToken == "" {
+		return nil, fmt.Errorf("OAuth consumer key and token must be configured: %w", discogs.ErrUnauthorized)
+	}
+
+	return []discogs.Option{
+		discogs.WithClient(&oauth.Client{
+			Credentials: oauth.Credentials{Token: c.conf.ConsumerKey, Secret: c.conf.ConsumerSecret},
+		}),
+		discogs.WithCredentials(&oauth.Credentials{Token: c.conf.OAuthToken, Secret: c.conf.OAuthSecret}),
+	}, nil
+}
+
+// flags returns a flag set with the output format flag registered.
+func (c *cli) flags(name string) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	format := fs.String("o", formatTable, "output format: table, json or yaml")
+	return fs, format
+}
+
+// parse parses flags interspersed with positional arguments and returns the
+// positional arguments.
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, &usageError{err.Error()}
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+func idArg(name string, args []string) (int, error) {
+	if len(args) != 1 {
+		return 0, usageErrorf("%s: expected exactly one id", name)
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, usageErrorf("%s: invalid id %q", name, args[0])
+	}
+	return id, nil
+}
+
+func pageFlags(fs *flag.FlagSet) *discogs.Pagination {
+	p := &discogs.Pagination{}
+	fs.IntVar(&p.Page, "page", 1, "page number")
+	fs.IntVar(&p.PerPage, "per-page", 50, "items per page")
+	return p
+}
+
+func releaseCmd(c *cli, args []string) error {
+	fs, format := c.flags("release")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := idArg("release", args)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.discogs()
+	if err != nil {
+		return err
+	}
+	release, err := client.Release(id)
+	if err != nil {
+		return err
+	}
+
+	return output(c.stdout, *format, release, func(tw *tabwriter.Writer) {
+		var labels, formats []string
+		for _, l := range release.Labels {
+			labels = append(labels, l.Name+" – "+l.Catno)
+		}
+		for _, f := range release.Formats {
+			formats = append(formats, f.Name+" ("+join(f.Descriptions)+")")
+		}
+
+		row(tw, "ID", release.ID)
+		row(tw, "Title", release.Title)
+		row(tw, "Artists", discogs.DisplayArtists(release.Artists))
+		row(tw, "Year", release.Year)
+		row(tw, "Country", release.Country)
+		row(tw, "Labels", join(labels))
+		row(tw, "Formats", join(formats))
+		row(tw, "Genres", join(release.Genres))
+		row(tw, "Styles", join(release.Styles))
+		if rt := release.Runtime(); rt.Duration > 0 {
+			row(tw, "Runtime", rt.Duration)
+		}
+		row(tw)
+		row(tw, "POSITION", "TITLE", "DURATION")
+		for _, t := range release.Tracklist {
+			row(tw, t.Position, t.Title, t.Duration)
+		}
+	})
+}
+
+func masterCmd(c *cli, args []string) error {
+	fs, format := c.flags("master")
+	versions := fs.Bool("versions", false, "list the versions of the master release")
+	var filter discogs.VersionFilter
+	fs.StringVar(&filter.Format, "format", "", "only list versions in this format")
+	fs.StringVar(&filter.Label, "label", "", "only list versions on this label")
+	fs.StringVar(&filter.Released, "released", "", "only list versions released in this year")
+	fs.StringVar(&filter.Country, "country", "", "only list versions released in this country")
+	page := pageFlags(fs)
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := idArg("master", args)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.discogs()
+	if err != nil {
+		return err
+	}
+
+	if *versions {
+		v, err := client.FilteredMasterVersions(id, filter, page)
+		if err != nil {
+			return err
+		}
+		return output(c.stdout, *format, v, func(tw *tabwriter.Writer) {
+			row(tw, "ID", "TITLE", "FORMAT", "LABEL", "CATNO", "COUNTRY", "RELEASED")
+			for _, v := range v.Versions {
+				row(tw, v.ID, v.Title, v.Format, v.Label, v.Catno, v.Country, v.Released)
+			}
+		})
+	}
+
+	master, err := client.Master(id)
+	if err != nil {
+		return err
+	}
+	return output(c.stdout, *format, master, func(tw *tabwriter.Writer) {
+		row(tw, "ID", master.ID)
+		row(tw, "Title", master.Title)
+		row(tw, "Artists", discogs.DisplayArtists(master.Artists))
+		row(tw, "Year", master.Year)
+		row(tw, "Main release", master.MainRelease)
+		row(tw, "Genres", join(master.Genres))
+		row(tw, "Styles", join(master.Styles))
+		row(tw)
+		row(tw, "POSITION", "TITLE", "DURATION")
+		for _, t := range master.Tracklist {
+			row(tw, t.Position, t.Title, t.Duration)
+		}
+	})
+}
+
+func artistCmd(c *cli, args []string) error {
+	fs, format := c.flags("artist")
+	releases := fs.Bool("releases", false, "list the releases of the artist")
+	page := pageFlags(fs)
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := idArg("artist", args)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.discogs()
+	if err != nil {
+		return err
+	}
+
+	if *releases {
+		r, err := client.ArtistReleases(id, page)
+		if err != nil {
+			return err
+		}
+		return output(c.stdout, *format, r, func(tw *tabwriter.Writer) {
+			releasesTable(tw, r.Releases)
+		})
+	}
+
+	artist, err := client.Artist(id)
+	if err != nil {
+		return err
+	}
+	return output(c.stdout, *format, artist, func(tw *tabwriter.Writer) {
+		var aliases, members []string
+		for _, a := range artist.Aliases {
+			aliases = append(aliases, a.Name)
+		}
+		for _, m := range artist.Members {
+			members = append(members, m.Name)
+		}
+
+		row(tw, "ID", artist.ID)
+		row(tw, "Name", artist.Name)
+		row(tw, "Real name", artist.Realname)
+		row(tw, "Aliases", join(aliases))
+		row(tw, "Members", join(members))
+		row(tw, "URI", artist.URI)
+	})
+}
+
+func labelCmd(c *cli, args []string) error {
+	fs, format := c.flags("label")
+	releases := fs.Bool("releases", false, "list the releases of the label")
+	page := pageFlags(fs)
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := idArg("label", args)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.discogs()
+	if err != nil {
+		return err
+	}
+
+	if *releases {
+		r, err := client.LabelReleases(id, page)
+		if err != nil {
+			return err
+		}
+		return output(c.stdout, *format, r, func(tw *tabwriter.Writer) {
+			releasesTable(tw, r.Releases)
+		})
+	}
+
+	label, err := client.Label(id)
+	if err != nil {
+		return err
+	}
+	return output(c.stdout, *format, label, func(tw *tabwriter.Writer) {
+		var sublabels []string
+		for _, s := range label.Sublabels {
+			sublabels = append(sublabels, s.Name)
+		}
+
+		row(tw, "ID", label.ID)
+		row(tw, "Name", label.Name)
+		row(tw, "Contact", label.ContactInfo)
+		row(tw, "Sublabels", join(sublabels))
+		row(tw, "URI", label.URI)
+	})
+}
+
+func releasesTable(tw *tabwriter.Writer, releases []discogs.ReleaseSource) {
+	row(tw, "ID", "TYPE", "ROLE", "YEAR", "ARTIST", "TITLE", "FORMAT", "CATNO")
+	for _, r := range releases {
+		row(tw, r.ID, r.Type, r.Role, r.Year, r.Artist, r.Title, r.Format, r.Catno)
+	}
+}
+
+func searchCmd(c *cli, args []string) error {
+	fs, format := c.flags("search")
+
+	var req discogs.SearchRequest
+	fs.StringVar(&req.Q, "q", "", "search query")
+	fs.StringVar((*string)(&req.Type), "type", "", "one of release, master, artist, label")
+	fs.StringVar(&req.Title, "title", "", "search by combined \"Artist Name - Release Title\" title field")
+	fs.StringVar(&req.ReleaseTitle, "release-title", "", "search release titles")
+	fs.StringVar(&req.Credit, "credit", "", "search release credits")
+	fs.StringVar(&req.Artist, "artist", "", "search artist names")
+	fs.StringVar(&req.Anv, "anv", "", "search artist ANV")
+	fs.StringVar(&req.Label, "label", "", "search label names")
+	fs.StringVar(&req.Genre, "genre", "", "search genres")
+	fs.StringVar(&req.Style, "style", "", "search styles")
+	fs.StringVar(&req.Country, "country", "", "search release country")
+	fs.StringVar(&req.Year, "year", "", "search release year or range, like 1990-1999")
+	fs.StringVar(&req.Format, "format", "", "search formats")
+	fs.StringVar(&req.Catno, "catno", "", "search catalog number")
+	fs.StringVar(&req.Barcode, "barcode", "", "search barcodes")
+	fs.StringVar(&req.Track, "track", "", "search track titles")
+	fs.IntVar(&req.Page, "page", 1, "page number")
+	fs.IntVar(&req.PerPage, "per-page", 50, "items per page")
+	webURL := fs.String("url", "", "search URL copied from the Discogs website, replacing the other filters")
+
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if *webURL != "" {
+		if req, err = discogs.ParseSearchURL(*webURL); err != nil {
+			return &usageError{"search: " + err.Error()}
+		}
+	}
+	if len(args) > 0 {
+		if req.Q != "" {
+			return usageErrorf("search: unexpected arguments %q", args)
+		}
+		req.Q = join(args)
+	}
+	if err := req.Validate(); err != nil {
+		return &usageError{"search: " + err.Error()}
+	}
+
+	client, err := c.discogs()
+	if err != nil {
+		return err
+	}
+	// Searches are authenticated with OAuth when it is configured,
+	// so that results tell what the user owns and wants.
+	opts, _ := c.oauth()
+	search, err := client.SearchContext(context.Background(), req, opts...)
+	if err != nil {
+		return err
+	}
+
+	return output(c.stdout, *format, search, func(tw *tabwriter.Writer) {
+		row(tw, "TYPE", "ID", "YEAR", "TITLE", "FORMAT", "COUNTRY", "CATNO", "OWNED")
+		for _, r := range search.Results {
+			row(tw, r.Type, r.ID, r.Year, r.Title, join(r.Format), r.Country, r.Catno, owned(r.UserData))
+		}
+	})
+}
+
+// owned describes whether a search result is in the user's collection or wantlist.
+func owned(data *discogs.UserData) string {
+	switch {
+	case data == nil:
+		return ""
+	case data.InCollection && data.InWantlist:
+		return "collection, wantlist"
+	case data.InCollection:
+		return "collection"
+	case data.InWantlist:
+		return "wantlist"
+	default:
+		return "-"
+	}
+}
+
+func collectionCmd(c *cli, args []string) error {
+	fs, format := c.flags("collection")
+	exportFormat := fs.String("format", "csv", "export format: csv, jsonl or flat")
+	resolve := fs.Bool("resolve", false, "fetch the full release of every exported item")
+	apply := fs.Bool("apply", false, "change the collection instead of reporting what an import would do")
+	resume := fs.String("resume", "", "JSON report of an interrupted import to resume")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	switch {
+	case len(args) == 3 && args[0] == "import":
+	case len(args) == 2 && (args[0] == "folders" || args[0] == "export"):
+	default:
+		return usageErrorf("collection: usage: collection folders|export <username> or collection import <username> <file>")
+	}
+
+	opts, err := c.oauth()
+	if err != nil {
+		return err
+	}
+	client, err := c.discogs()
+	if err != nil {
+		return err
+	}
+
+	if args[0] == "export" {
+		f, err := export.ParseFormat(*exportFormat)
+		if err != nil {
+			return &usageError{err.Error()}
+		}
+		e := export.New(client, opts...)
+		e.Resolve = *resolve
+		_, err = e.Export(context.Background(), c.stdout, args[1], f)
+		return err
+	}
+
+	if args[0] == "import" {
+		return c.importCollection(client, opts, args[1], args[2], *apply, *resume, *format)
+	}
+
+	folders, err := client.GetFolders(context.Background(), args[1], opts...)
+	if err != nil {
+		return err
+	}
+
+	return output(c.stdout, *format, folders, func(tw *tabwriter.Writer) {
+		row(tw, "ID", "NAME", "COUNT")
+		for _, f := range folders.Folders {
+			row(tw, f.ID, f.Name, f.Count)
+		}
+	})
+}
+
+// importCollection imports the CSV file name into the collection of username and
+// writes the report. The report is written even if the import is interrupted,
+// so that it can be passed to --resume.
+func (c *cli) importCollection(client discogs.Discogs, opts []discogs.Option, username, name string, apply bool, resume, format string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	rows, err := importer.ReadRows(f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	i := importer.New(client, opts...)
+	i.DryRun = !apply
+	if resume != "" {
+		b, err := ioutil.ReadFile(resume)
+		if err != nil {
+			return err
+		}
+		i.Resume = &importer.Report{}
+		if err := json.Unmarshal(b, i.Resume); err != nil {
+			return fmt.Errorf("collection: invalid report %s: %w", resume, err)
+		}
+	}
+
+	report, err := i.Import(context.Background(), username, rows)
+	if oerr := output(c.stdout, format, report, func(tw *tabwriter.Writer) {
+		row(tw, "RECORD", "STATUS", "RELEASE", "FOLDER", "REASON")
+		for _, r := range report.Results {
+			row(tw, r.Record, r.Status, r.ReleaseID, r.Folder, r.Reason)
+		}
+	}); err == nil {
+		err = oerr
+	}
+	return err
+}
+
+func identityCmd(c *cli, args []string) error {
+	fs, format := c.flags("identity")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 0 {
+		return usageErrorf("identity: unexpected arguments %q", args)
+	}
+
+	opts, err := c.oauth()
+	if err != nil {
+		return err
+	}
+	client, err := c.discogs()
+	if err != nil {
+		return err
+	}
+	id, err := client.OAuthIdentity(context.Background(), opts...)
+	if err != nil {
+		return err
+	}
+
+	return output(c.stdout, *format, id, func(tw *tabwriter.Writer) {
+		row(tw, "ID", id.ID)
+		row(tw, "Username", id.Username)
+		row(tw, "Consumer", id.ConsumerName)
+	})
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// config holds the client settings. Values are read from the config file
+// and overridden by environment variables.
+type config struct {
+	Token          string `yaml:"token"`
+	UserAgent      string `yaml:"user_agent"`
+	Currency       string `yaml:"currency"`
+	URL            string `yaml:"url"`
+	ConsumerKey    string `yaml:"consumer_key"`
+	ConsumerSecret string `yaml:"consumer_secret"`
+	OAuthToken     string `yaml:"oauth_token"`
+	OAuthSecret    string `yaml:"oauth_secret"`
+}
+
+const defaultUserAgent = "go-discogs-cli"
+
+// configPath returns the config file location: $DISCOGS_CONFIG, or
+// discogs/config.yaml in the user config directory.
+func configPath(getenv func(string) string) string {
+	if p := getenv("DISCOGS_CONFIG"); p != "" {
+		return p
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "discogs", "config.yaml")
+}
+
+// loadConfig reads the config file, if any, and applies environment overrides.
+func loadConfig(getenv func(string) string) (*config, error) {
+	c := &config{}
+
+	if path := configPath(getenv); path != "" {
+		data, err := ioutil.ReadFile(path)
+		switch {
+		case os.IsNotExist(err):
+		case err != nil:
+			return nil, err
+		default:
+			if err := yaml.Unmarshal(data, c); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	env := []struct {
+		name  string
+		value *string
+	}{
+		{"DISCOGS_TOKEN", &c.Token},
+		{"DISCOGS_USER_AGENT", &c.UserAgent},
+		{"DISCOGS_CURRENCY", &c.Currency},
+		{"DISCOGS_URL", &c.URL},
+		{"DISCOGS_CONSUMER_KEY", &c.ConsumerKey},
+		{"DISCOGS_CONSUMER_SECRET", &c.ConsumerSecret},
+		{"DISCOGS_OAUTH_TOKEN", &c.OAuthToken},
+		{"DISCOGS_OAUTH_SECRET", &c.OAuthSecret},
+	}
+	for _, e := range env {
+		if v := getenv(e.name); v != "" {
+			*e.value = v
+		}
+	}
+
+	if c.UserAgent == "" {
+		c.UserAgent = defaultUserAgent
+	}
+	return c, nil
+}
@@ -0,0 +1,132 @@
+// Command discogs is a command-line client for the Discogs API.
+//
+// Usage:
+//
+//	discogs release <id>
+//	discogs master <id> [--versions]
+//	discogs artist <id> [--releases]
+//	discogs label <id> [--releases]
+//	discogs search [--q query] [--artist name] [--year year|from-to] [--format format] ...
+//	discogs search --url https://www.discogs.com/search/?q=...
+//	discogs collection folders <username>
+//	discogs collection export <username> [--format csv|jsonl|flat] [--resolve]
+//	discogs collection import <username> <file> [--apply] [--resume report.json]
+//	discogs identity
+//
+// Every command accepts -o table|json|yaml to select the output format.
+//
+// Settings are read from $DISCOGS_CONFIG (default is discogs/config.yaml in the
+// user config directory) and the DISCOGS_TOKEN, DISCOGS_USER_AGENT,
+// DISCOGS_CURRENCY, DISCOGS_URL, DISCOGS_CONSUMER_KEY, DISCOGS_CONSUMER_SECRET,
+// DISCOGS_OAUTH_TOKEN and DISCOGS_OAUTH_SECRET environment variables.
+// The collection and identity commands require the OAuth settings.
+// collection import only reports what it would do unless --apply is given;
+// save its -o json output to resume an import interrupted by the rate limit.
+//
+// Exit codes: 0 success, 1 error, 2 usage error, 3 authentication required,
+// 4 not found, 5 rate limited.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ninnemana/go-discogs"
+)
+
+// Exit codes.
+const (
+	exitOK           = 0
+	exitError        = 1
+	exitUsage        = 2
+	exitUnauthorized = 3
+	exitNotFound     = 4
+	exitRateLimited  = 5
+)
+
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usageErrorf(format string, args ...interface{}) error {
+	return &usageError{fmt.Sprintf(format, args...)}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr, os.Getenv))
+}
+
+func run(args []string, stdout, stderr io.Writer, getenv func(string) string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		usage(stderr)
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "discogs: unknown command %q\n", args[0])
+		usage(stderr)
+		return exitUsage
+	}
+
+	conf, err := loadConfig(getenv)
+	if err != nil {
+		fmt.Fprintf(stderr, "discogs: failed to load config: %s\n", err)
+		return exitError
+	}
+
+	c := &cli{conf: conf, stdout: stdout, stderr: stderr}
+	if err := cmd.run(c, args[1:]); err != nil {
+		fmt.Fprintf(stderr, "discogs: %s\n", err)
+		return exitCode(err)
+	}
+	return exitOK
+}
+
+// exitCode maps an error to the process exit code.
+func exitCode(err error) int {
+	var uerr *usageError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &uerr):
+		return exitUsage
+	case errors.Is(err, discogs.ErrUnauthorized):
+		return exitUnauthorized
+	case errors.Is(err, discogs.ErrNotFound):
+		return exitNotFound
+	case errors.Is(err, discogs.ErrTooManyRequests):
+		return exitRateLimited
+	default:
+		return exitError
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: discogs <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-12s %s\n", name, commands[name].usage)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "flags:")
+	fmt.Fprintln(w, "  -o "+strings.Join([]string{formatTable, formatJSON, formatYAML}, "|")+"  output format")
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ninnemana/go-discogs"
+	"github.com/ninnemana/go-discogs/discogstest"
+)
+
+func TestRun(t *testing.T) {
+	s := discogstest.NewServer()
+	defer s.Close()
+
+	s.AddRelease(discogs.Release{
+		ID:        8138518,
+		Title:     "Elephant Riddim",
+		Year:      2016,
+		Artists:   []discogs.ArtistSource{{ID: 794217, Name: "St. Petersburg Ska-Jazz Review"}},
+		Tracklist: []discogs.Track{{Position: "A1", Title: "Action Movie"}},
+	})
+
+	env := map[string]string{
+		"DISCOGS_CONFIG": "testdata/none.yaml",
+		"DISCOGS_URL":    s.URL,
+	}
+	getenv := func(k string) string { return env[k] }
+
+	tests := map[string]struct {
+		args   []string
+		code   int
+		output string
+	}{
+		"table":       {[]string{"release", "8138518"}, exitOK, "Elephant Riddim"},
+		"yaml":        {[]string{"release", "8138518", "-o", "yaml"}, exitOK, "title: Elephant Riddim"},
+		"not found":   {[]string{"release", "1"}, exitNotFound, ""},
+		"bad id":      {[]string{"release", "abc"}, exitUsage, ""},
+		"no command":  {[]string{"unknown"}, exitUsage, ""},
+		"search auth": {[]string{"search", "--q", "riddim"}, exitUnauthorized, ""},
+		"no oauth":    {[]string{"identity"}, exitUnauthorized, ""},
+	}
+	for name := range tests {
+		tt := tests[name]
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(tt.args, &stdout, &stderr, getenv); code != tt.code {
+				t.Errorf("code got=%d; want=%d (%s)", code, tt.code, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.output) {
+				t.Errorf("output got=%q; want to contain %q", stdout.String(), tt.output)
+			}
+		})
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	s := discogstest.NewServer()
+	defer s.Close()
+	s.AddMaster(discogs.Master{ID: 718441, Title: "Infinite"},
+		discogs.Version{ID: 3221262, Title: "Infinite", Country: "US"},
+	)
+
+	env := map[string]string{
+		"DISCOGS_CONFIG": "testdata/none.yaml",
+		"DISCOGS_URL":    s.URL,
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"master", "718441", "--versions", "-o", "json"}, &stdout, &stderr, func(k string) string { return env[k] }); code != exitOK {
+		t.Fatalf("code got=%d; want=%d (%s)", code, exitOK, stderr.String())
+	}
+
+	var versions discogs.MasterVersions
+	if err := json.Unmarshal(stdout.Bytes(), &versions); err != nil {
+		t.Fatalf("failed to unmarshal output: %s", err)
+	}
+	if len(versions.Versions) != 1 || versions.Versions[0].Country != "US" {
+		t.Errorf("versions got=%+v", versions.Versions)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats accepted by the -o flag.
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+)
+
+// output writes v in the given format. table renders v for the table format.
+func output(w io.Writer, format string, v interface{}, table func(tw *tabwriter.Writer)) error {
+	switch format {
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case formatYAML:
+		return writeYAML(w, v)
+	case formatTable, "":
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		table(tw)
+		return tw.Flush()
+	default:
+		return usageErrorf("unknown output format %q", format)
+	}
+}
+
+// writeYAML writes v as YAML using the JSON field names of the API types.
+// The JSON encoding is parsed as a YAML node tree, which keeps the field
+// order, and re-emitted in block style.
+func writeYAML(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	blockStyle(&node)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func blockStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
+
+// row writes tab separated values as a table row.
+func row(tw *tabwriter.Writer, values ...interface{}) {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = fmt.Sprint(v)
+	}
+	fmt.Fprintln(tw, strings.Join(s, "\t"))
+}
+
+func join(values []string) string {
+	return strings.Join(values, ", ")
+}
@@ -2,6 +2,9 @@ package discogs
 
 import (
 	"context"
+	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/gomodule/oauth1/oauth"
@@ -10,21 +13,36 @@ import (
 
 type CollectionService interface {
 	GetFolders(ctx context.Context, username string, options ...Option) (*CollectionResponse, error)
+	// GetCollectionItems returns the releases in a folder of the user's collection.
+	GetCollectionItems(ctx context.Context, username string, folderID int, pagination *Pagination, options ...Option) (*CollectionItems, error)
+	// GetCollectionFields returns the custom fields of the user's collection.
+	GetCollectionFields(ctx context.Context, username string, options ...Option) (*CollectionFields, error)
+	// CreateFolder creates a folder in the user's collection.
+	CreateFolder(ctx context.Context, username, name string, options ...Option) (*Folder, error)
+	// AddToFolder adds an instance of a release to a folder of the user's collection.
+	AddToFolder(ctx context.Context, username string, folderID, releaseID int, options ...Option) (*Instance, error)
+	// SetInstanceRating sets the rating (0 to 5) of a release instance in the user's collection.
+	SetInstanceRating(ctx context.Context, username string, folderID, releaseID, instanceID, rating int, options ...Option) error
+	// SetInstanceField sets the value of a custom field of a release instance in the user's collection.
+	SetInstanceField(ctx context.Context, username string, folderID, releaseID, instanceID, fieldID int, value string, options ...Option) error
 }
 
 type collectionService struct {
+	client      *client
 	url         string
 	oauthClient *oauth.Client
 	creds       *oauth.Credentials
 }
 
 const (
-	collectionsURI = "/users/{username}/collection/folders"
+	collectionsURI      = "/users/{username}/collection/folders"
+	collectionFieldsURI = "/users/{username}/collection/fields"
 )
 
-func newCollectionService(url string) CollectionService {
+func newCollectionService(c *client, url string) CollectionService {
 	return &collectionService{
-		url: url,
+		client: c,
+		url:    url,
 	}
 }
 
@@ -43,11 +61,13 @@ func (c *collectionService) GetFolders(ctx context.Context, username string, opt
 	ctx, span := trace.StartSpan(ctx, "ninnemana.discogs.GetFolders")
 	defer span.End()
 
+	// options apply to this call only, the service is shared
+	cc := *c
 	for _, opts := range options {
-		opts(c)
+		opts(&cc)
 	}
 
-	route := c.url + strings.Replace(collectionsURI, "{username}", username, 1)
+	route := cc.url + strings.Replace(collectionsURI, "{username}", username, 1)
 
 	span.AddAttributes(
 		trace.StringAttribute("username", username),
@@ -56,11 +76,11 @@ func (c *collectionService) GetFolders(ctx context.Context, username string, opt
 
 	var collection CollectionResponse
 
-	if err := requestWithCreds(
+	if err := cc.client.requestWithCreds(
 		ctx,
 		route,
-		c.oauthClient,
-		c.creds,
+		cc.oauthClient,
+		cc.creds,
 		nil,
 		&collection,
 	); err != nil {
@@ -74,3 +94,313 @@ func (c *collectionService) GetFolders(ctx context.Context, username string, opt
 
 	return &collection, nil
 }
+
+// CollectionItems is a page of releases in a collection folder.
+type CollectionItems struct {
+	Pagination Page             `json:"pagination"`
+	Releases   []CollectionItem `json:"releases"`
+}
+
+// CollectionItem is an instance of a release in a user's collection.
+type CollectionItem struct {
+	ID               int              `json:"id"`
+	InstanceID       int              `json:"instance_id"`
+	FolderID         int              `json:"folder_id"`
+	Rating           int              `json:"rating"`
+	DateAdded        string           `json:"date_added"`
+	BasicInformation BasicInformation `json:"basic_information"`
+	Notes            []Note           `json:"notes,omitempty"`
+}
+
+// BasicInformation is the summary of a release embedded in collection items.
+type BasicInformation struct {
+	ID          int            `json:"id"`
+	MasterID    int            `json:"master_id"`
+	Title       string         `json:"title"`
+	Year        int            `json:"year"`
+	ResourceURL string         `json:"resource_url"`
+	Thumb       string         `json:"thumb"`
+	CoverImage  string         `json:"cover_image"`
+	Formats     []Format       `json:"formats"`
+	Labels      []LabelSource  `json:"labels"`
+	Artists     []ArtistSource `json:"artists"`
+	Genres      []string       `json:"genres"`
+	Styles      []string       `json:"styles"`
+}
+
+// Note is the value of a custom field of a collection item.
+type Note struct {
+	FieldID int    `json:"field_id"`
+	Value   string `json:"value"`
+}
+
+// CollectionFields lists the custom fields of a user's collection.
+type CollectionFields struct {
+	Fields []Field `json:"fields"`
+}
+
+// Field is a custom collection field. Type is either "dropdown" or "textarea".
+type Field struct {
+	ID       int      `json:"id"`
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Position int      `json:"position"`
+	Public   bool     `json:"public"`
+	Options  []string `json:"options,omitempty"`
+	Lines    int      `json:"lines,omitempty"`
+}
+
+func (c *collectionService) GetCollectionItems(ctx context.Context, username string, folderID int, pagination *Pagination, options ...Option) (*CollectionItems, error) {
+	ctx, span := trace.StartSpan(ctx, "ninnemana.discogs.GetCollectionItems")
+	defer span.End()
+
+	if err := pagination.Validate(CollectionItemsSortKeys); err != nil {
+		return nil, err
+	}
+
+	// options apply to this call only, the service is shared
+	cc := *c
+	for _, opts := range options {
+		opts(&cc)
+	}
+
+	route := cc.url + strings.Replace(collectionsURI, "{username}", username, 1) + "/" + strconv.Itoa(folderID) + "/releases"
+
+	span.AddAttributes(
+		trace.StringAttribute("username", username),
+		trace.Int64Attribute("folder_id", int64(folderID)),
+		trace.StringAttribute("route", route),
+	)
+
+	var items CollectionItems
+
+	if err := cc.client.requestWithCreds(
+		ctx,
+		route,
+		cc.oauthClient,
+		cc.creds,
+		pagination.params(),
+		&items,
+	); err != nil {
+		span.SetStatus(trace.Status{
+			Code: trace.StatusCodeInternal,
+		})
+		span.AddAttributes(trace.StringAttribute("err", err.Error()))
+
+		return nil, err
+	}
+
+	return &items, nil
+}
+
+func (c *collectionService) GetCollectionFields(ctx context.Context, username string, options ...Option) (*CollectionFields, error) {
+	ctx, span := trace.StartSpan(ctx, "ninnemana.discogs.GetCollectionFields")
+	defer span.End()
+
+	// options apply to this call only, the service is shared
+	cc := *c
+	for _, opts := range options {
+		opts(&cc)
+	}
+
+	route := cc.url + strings.Replace(collectionFieldsURI, "{username}", username, 1)
+
+	span.AddAttributes(
+		trace.StringAttribute("username", username),
+		trace.StringAttribute("route", route),
+	)
+
+	var fields CollectionFields
+
+	if err := cc.client.requestWithCreds(
+		ctx,
+		route,
+		cc.oauthClient,
+		cc.creds,
+		nil,
+		&fields,
+	); err != nil {
+		span.SetStatus(trace.Status{
+			Code: trace.StatusCodeInternal,
+		})
+		span.AddAttributes(trace.StringAttribute("err", err.Error()))
+
+		return nil, err
+	}
+
+	return &fields, nil
+}
+
+// Instance identifies a release instance added to a collection.
+type Instance struct {
+	InstanceID  int    `json:"instance_id"`
+	ResourceURL string `json:"resource_url"`
+}
+
+// instanceRoute returns the route of a release instance in a collection folder.
+func (c *collectionService) instanceRoute(username string, folderID, releaseID, instanceID int) string {
+	return c.url + strings.Replace(collectionsURI, "{username}", username, 1) +
+		"/" + strconv.Itoa(folderID) + "/releases/" + strconv.Itoa(releaseID) + "/instances/" + strconv.Itoa(instanceID)
+}
+
+func (c *collectionService) CreateFolder(ctx context.Context, username, name string, options ...Option) (*Folder, error) {
+	ctx, span := trace.StartSpan(ctx, "ninnemana.discogs.CreateFolder")
+	defer span.End()
+
+	// options apply to this call only, the service is shared
+	cc := *c
+	for _, opts := range options {
+		opts(&cc)
+	}
+
+	route := cc.url + strings.Replace(collectionsURI, "{username}", username, 1)
+
+	span.AddAttributes(
+		trace.StringAttribute("username", username),
+		trace.StringAttribute("name", name),
+		trace.StringAttribute("route", route),
+	)
+
+	var folder Folder
+
+	if err := cc.client.sendWithCreds(
+		ctx,
+		http.MethodPost,
+		route,
+		cc.oauthClient,
+		cc.creds,
+		url.Values{"name": {name}},
+		&folder,
+	); err != nil {
+		span.SetStatus(trace.Status{
+			Code: trace.StatusCodeInternal,
+		})
+		span.AddAttributes(trace.StringAttribute("err", err.Error()))
+
+		return nil, err
+	}
+
+	return &folder, nil
+}
+
+func (c *collectionService) AddToFolder(ctx context.Context, username string, folderID, releaseID int, options ...Option) (*Instance, error) {
+	ctx, span := trace.StartSpan(ctx, "ninnemana.discogs.AddToFolder")
+	defer span.End()
+
+	// options apply to this call only, the service is shared
+	cc := *c
+	for _, opts := range options {
+		opts(&cc)
+	}
+
+	route := cc.url + strings.Replace(collectionsURI, "{username}", username, 1) +
+		"/" + strconv.Itoa(folderID) + "/releases/" + strconv.Itoa(releaseID)
+
+	span.AddAttributes(
+		trace.StringAttribute("username", username),
+		trace.Int64Attribute("folder_id", int64(folderID)),
+		trace.Int64Attribute("release_id", int64(releaseID)),
+		trace.StringAttribute("route", route),
+	)
+
+	var instance Instance
+
+	if err := cc.client.sendWithCreds(
+		ctx,
+		http.MethodPost,
+		route,
+		cc.oauthClient,
+		cc.creds,
+		nil,
+		&instance,
+	); err != nil {
+		span.SetStatus(trace.Status{
+			Code: trace.StatusCodeInternal,
+		})
+		span.AddAttributes(trace.StringAttribute("err", err.Error()))
+
+		return nil, err
+	}
+
+	return &instance, nil
+}
+
+func (c *collectionService) SetInstanceRating(ctx context.Context, username string, folderID, releaseID, instanceID, rating int, options ...Option) error {
+	ctx, span := trace.StartSpan(ctx, "ninnemana.discogs.SetInstanceRating")
+	defer span.End()
+
+	if rating < 0 || rating > 5 {
+		return ErrInvalidRating
+	}
+
+	// options apply to this call only, the service is shared
+	cc := *c
+	for _, opts := range options {
+		opts(&cc)
+	}
+
+	route := cc.instanceRoute(username, folderID, releaseID, instanceID)
+
+	span.AddAttributes(
+		trace.StringAttribute("username", username),
+		trace.Int64Attribute("rating", int64(rating)),
+		trace.StringAttribute("route", route),
+	)
+
+	if err := cc.client.sendWithCreds(
+		ctx,
+		http.MethodPost,
+		route,
+		cc.oauthClient,
+		cc.creds,
+		url.Values{"rating": {strconv.Itoa(rating)}},
+		nil,
+	); err != nil {
+		span.SetStatus(trace.Status{
+			Code: trace.StatusCodeInternal,
+		})
+		span.AddAttributes(trace.StringAttribute("err", err.Error()))
+
+		return err
+	}
+
+	return nil
+}
+
+func (c *collectionService) SetInstanceField(ctx context.Context, username string, folderID, releaseID, instanceID, fieldID int, value string, options ...Option) error {
+	ctx, span := trace.StartSpan(ctx, "ninnemana.discogs.SetInstanceField")
+	defer span.End()
+
+	// options apply to this call only, the service is shared
+	cc := *c
+	for _, opts := range options {
+		opts(&cc)
+	}
+
+	route := cc.instanceRoute(username, folderID, releaseID, instanceID) + "/fields/" + strconv.Itoa(fieldID)
+
+	span.AddAttributes(
+		trace.StringAttribute("username", username),
+		trace.Int64Attribute("field_id", int64(fieldID)),
+		trace.StringAttribute("route", route),
+	)
+
+	if err := cc.client.sendWithCreds(
+		ctx,
+		http.MethodPost,
+		route,
+		cc.oauthClient,
+		cc.creds,
+		url.Values{"value": {value}},
+		nil,
+	); err != nil {
+		span.SetStatus(trace.Status{
+			Code: trace.StatusCodeInternal,
+		})
+		span.AddAttributes(trace.StringAttribute("err", err.Error()))
+
+		return err
+	}
+
+	return nil
+}
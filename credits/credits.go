@@ -0,0 +1,283 @@
+// Package credits turns the extra artists of a release into per-track credits
+// and an index of the roles of every artist.
+//
+// Discogs credits pack several roles into one string, like
+// "Producer, Mixed By [Assistant]", and point at tracks with ranges like
+// "A1 to A3, B2". Build splits the roles, parses their bracketed qualifiers
+// and expands the ranges onto the positions of the tracklist:
+//
+//	c := credits.FromRelease(release)
+//	for _, t := range c.Tracks {
+//		for _, cr := range t.Credits {
+//			fmt.Println(t.Track.Position, cr.Role.Name, cr.Artist.Name)
+//		}
+//	}
+package credits
+
+import (
+	"strings"
+
+	"github.com/ninnemana/go-discogs"
+)
+
+// Role is a single credited role, like "Mixed By" qualified by "Assistant".
+type Role struct {
+	Name       string   `json:"name"`
+	Qualifiers []string `json:"qualifiers,omitempty"`
+}
+
+// String returns the role the way Discogs writes it, like "Mixed By [Assistant]".
+func (r Role) String() string {
+	if len(r.Qualifiers) == 0 {
+		return r.Name
+	}
+	return r.Name + " [" + strings.Join(r.Qualifiers, ", ") + "]"
+}
+
+// ParseRoles splits a compound role such as "Producer, Performer [Guitar, Bass]"
+// into its roles. Commas inside brackets separate qualifiers, not roles.
+func ParseRoles(s string) []Role {
+	var roles []Role
+	for _, part := range splitOutside(s, ',') {
+		if r, ok := parseRole(part); ok {
+			roles = append(roles, r)
+		}
+	}
+	return roles
+}
+
+func parseRole(s string) (Role, bool) {
+	var (
+		r    Role
+		name strings.Builder
+	)
+	for {
+		open := strings.IndexByte(s, '[')
+		if open < 0 {
+			name.WriteString(s)
+			break
+		}
+		end := strings.IndexByte(s[open:], ']')
+		if end < 0 {
+			// an unclosed bracket is part of the name
+			name.WriteString(s)
+			break
+		}
+		name.WriteString(s[:open])
+		for _, q := range strings.Split(s[open+1:open+end], ",") {
+			if q = strings.TrimSpace(q); q != "" {
+				r.Qualifiers = append(r.Qualifiers, q)
+			}
+		}
+		s = s[open+end+1:]
+	}
+	r.Name = strings.Join(strings.Fields(name.String()), " ")
+	return r, r.Name != ""
+}
+
+// splitOutside splits s at sep, except between square brackets.
+func splitOutside(s string, sep rune) []string {
+	var (
+		out   []string
+		depth int
+		start int
+	)
+	for i, c := range s {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			if depth > 0 {
+				depth--
+			}
+		case sep:
+			if depth == 0 {
+				out = append(out, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(out, s[start:])
+}
+
+// Credit is one role of an artist, on the whole release or on some tracks.
+type Credit struct {
+	Artist discogs.ArtistSource `json:"artist"`
+	Role   Role                 `json:"role"`
+	// Tracks holds the positions the credit applies to; it is empty for release-wide credits.
+	Tracks []string `json:"tracks,omitempty"`
+}
+
+// TrackCredits are the credits of one track: its own extra artists
+// followed by the release credits pointing at it.
+type TrackCredits struct {
+	Track   discogs.Track `json:"track"`
+	Credits []Credit      `json:"credits,omitempty"`
+}
+
+// ArtistRoles are the distinct roles of an artist on a release.
+type ArtistRoles struct {
+	Artist discogs.ArtistSource `json:"artist"`
+	Roles  []Role               `json:"roles"`
+}
+
+// Credits are the credits of a release.
+type Credits struct {
+	// Release holds the credits that apply to the whole release.
+	Release []Credit `json:"release,omitempty"`
+	// Tracks holds every positioned track in tracklist order, sub tracks included.
+	Tracks []TrackCredits `json:"tracks"`
+	// Artists holds the roles of every credited artist, in order of appearance.
+	Artists []ArtistRoles `json:"artists"`
+}
+
+// FromRelease builds the credits of a release.
+func FromRelease(r *discogs.Release) *Credits {
+	return Build(r.ExtraArtists, r.Tracklist)
+}
+
+// Build builds the credits of the release-level extra artists and the tracklist.
+func Build(extra []discogs.ArtistSource, tracklist []discogs.Track) *Credits {
+	c := &Credits{}
+	index := map[string]int{}
+	var positions []string
+	for _, t := range flatten(tracklist) {
+		if _, ok := index[t.Position]; ok {
+			continue
+		}
+		index[t.Position] = len(c.Tracks)
+		positions = append(positions, t.Position)
+		c.Tracks = append(c.Tracks, TrackCredits{Track: t})
+	}
+
+	for i := range c.Tracks {
+		t := c.Tracks[i].Track
+		for _, a := range t.Extraartists {
+			for _, role := range ParseRoles(a.Role) {
+				cr := Credit{Artist: a, Role: role, Tracks: []string{t.Position}}
+				c.Tracks[i].Credits = append(c.Tracks[i].Credits, cr)
+				c.addRole(a, role)
+			}
+		}
+	}
+
+	for _, a := range extra {
+		tracks := ExpandTracks(a.Tracks, positions)
+		for _, role := range ParseRoles(a.Role) {
+			cr := Credit{Artist: a, Role: role, Tracks: tracks}
+			if len(tracks) == 0 {
+				c.Release = append(c.Release, cr)
+			}
+			for _, p := range tracks {
+				if i, ok := index[p]; ok {
+					c.Tracks[i].Credits = append(c.Tracks[i].Credits, cr)
+				}
+			}
+			c.addRole(a, role)
+		}
+	}
+	return c
+}
+
+func (c *Credits) addRole(a discogs.ArtistSource, role Role) {
+	for i := range c.Artists {
+		ar := &c.Artists[i]
+		if ar.Artist.ID != a.ID || (a.ID == 0 && ar.Artist.Name != a.Name) {
+			continue
+		}
+		for _, r := range ar.Roles {
+			if r.String() == role.String() {
+				return
+			}
+		}
+		ar.Roles = append(ar.Roles, role)
+		return
+	}
+	c.Artists = append(c.Artists, ArtistRoles{Artist: a, Roles: []Role{role}})
+}
+
+// Roles returns the roles of the artist with the given ID.
+func (c *Credits) Roles(artistID int) []Role {
+	for _, ar := range c.Artists {
+		if ar.Artist.ID == artistID {
+			return ar.Roles
+		}
+	}
+	return nil
+}
+
+// Track returns the credits of the track at a position.
+func (c *Credits) Track(position string) (TrackCredits, bool) {
+	for _, t := range c.Tracks {
+		if t.Track.Position == position {
+			return t, true
+		}
+	}
+	return TrackCredits{}, false
+}
+
+// flatten returns the positioned tracks of a tracklist, sub tracks following their index track.
+func flatten(tracks []discogs.Track) []discogs.Track {
+	var out []discogs.Track
+	for _, t := range tracks {
+		if t.Position != "" && t.Type != discogs.TrackTypeHeading {
+			out = append(out, t)
+		}
+		out = append(out, flatten(t.SubTracks)...)
+	}
+	return out
+}
+
+// ExpandTracks expands a track list such as "A1 to A3, B2" onto positions,
+// which are in tracklist order. Ranges run from their first to their last
+// position in that order; positions or range ends missing from the tracklist
+// are kept as written.
+func ExpandTracks(s string, positions []string) []string {
+	var (
+		out  []string
+		seen = map[string]bool{}
+	)
+	add := func(p string) {
+		if p != "" && !seen[p] {
+			seen[p] = true
+			out = append(out, p)
+		}
+	}
+
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '&' }) {
+		part = strings.TrimSpace(part)
+		from, to, ok := cutRange(part)
+		if !ok {
+			add(part)
+			continue
+		}
+
+		first, last := indexOf(positions, from), indexOf(positions, to)
+		if first < 0 || last < 0 || first > last {
+			add(from)
+			add(to)
+			continue
+		}
+		for _, p := range positions[first : last+1] {
+			add(p)
+		}
+	}
+	return out
+}
+
+func cutRange(s string) (string, string, bool) {
+	fields := strings.Fields(s)
+	if len(fields) != 3 || !strings.EqualFold(fields[1], "to") {
+		return "", "", false
+	}
+	return fields[0], fields[2], true
+}
+
+func indexOf(positions []string, p string) int {
+	for i, pos := range positions {
+		if pos == p {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,99 @@
+package credits
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ninnemana/go-discogs"
+)
+
+func TestParseRoles(t *testing.T) {
+	tests := map[string][]Role{
+		"Producer":                         {{Name: "Producer"}},
+		"Producer, Mixed By [Assistant]":   {{Name: "Producer"}, {Name: "Mixed By", Qualifiers: []string{"Assistant"}}},
+		"Performer [Guitar, Bass], Vocals": {{Name: "Performer", Qualifiers: []string{"Guitar", "Bass"}}, {Name: "Vocals"}},
+		"Voice [Uncredited]":               {{Name: "Voice", Qualifiers: []string{"Uncredited"}}},
+		" , ":                              nil,
+	}
+	for in, want := range tests {
+		if got := ParseRoles(in); !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: got=%+v; want=%+v", in, got, want)
+		}
+	}
+
+	r := Role{Name: "Performer", Qualifiers: []string{"Guitar", "Bass"}}
+	if r.String() != "Performer [Guitar, Bass]" {
+		t.Errorf("string got=%q", r.String())
+	}
+}
+
+func TestExpandTracks(t *testing.T) {
+	positions := []string{"A1", "A2", "A3", "B1", "B2", "B3"}
+	tests := map[string][]string{
+		"":              nil,
+		"B2":            {"B2"},
+		"A1 to A3, B2":  {"A1", "A2", "A3", "B2"},
+		"A3 to B1 & A1": {"A3", "B1", "A1"},
+		"A2, A2":        {"A2"},
+		"C1 to C3":      {"C1", "C3"},
+	}
+	for in, want := range tests {
+		if got := ExpandTracks(in, positions); !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: got=%q; want=%q", in, got, want)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	producer := discogs.ArtistSource{ID: 1, Name: "Producer", Role: "Producer, Mixed By [Assistant]"}
+	guitar := discogs.ArtistSource{ID: 2, Name: "Guitarist", Role: "Guitar", Tracks: "A1 to A2, B1a"}
+	release := &discogs.Release{
+		ExtraArtists: []discogs.ArtistSource{producer, guitar},
+		Tracklist: []discogs.Track{
+			{Position: "A1", Type: discogs.TrackTypeTrack, Extraartists: []discogs.ArtistSource{
+				{ID: 2, Name: "Guitarist", Role: "Vocals [Uncredited]"},
+			}},
+			{Position: "A2", Type: discogs.TrackTypeTrack},
+			{Type: discogs.TrackTypeHeading, Title: "Side B"},
+			{Position: "B1", Type: discogs.TrackTypeIndex, SubTracks: []discogs.Track{
+				{Position: "B1a", Type: discogs.TrackTypeTrack},
+				{Position: "B1b", Type: discogs.TrackTypeTrack},
+			}},
+		},
+	}
+
+	c := FromRelease(release)
+
+	if len(c.Release) != 2 || c.Release[1].Role.String() != "Mixed By [Assistant]" {
+		t.Errorf("release credits got=%+v", c.Release)
+	}
+
+	var positions []string
+	for _, tc := range c.Tracks {
+		positions = append(positions, tc.Track.Position)
+	}
+	if !reflect.DeepEqual(positions, []string{"A1", "A2", "B1", "B1a", "B1b"}) {
+		t.Errorf("positions got=%q", positions)
+	}
+
+	a1, _ := c.Track("A1")
+	if len(a1.Credits) != 2 || a1.Credits[0].Role.Name != "Vocals" || a1.Credits[1].Role.Name != "Guitar" {
+		t.Errorf("A1 credits got=%+v", a1.Credits)
+	}
+	b1a, _ := c.Track("B1a")
+	if len(b1a.Credits) != 1 {
+		t.Errorf("B1a credits got=%+v", b1a.Credits)
+	}
+	b1b, _ := c.Track("B1b")
+	if len(b1b.Credits) != 0 {
+		t.Errorf("B1b credits got=%+v", b1b.Credits)
+	}
+
+	roles := c.Roles(2)
+	if len(roles) != 2 || roles[0].String() != "Vocals [Uncredited]" || roles[1].Name != "Guitar" {
+		t.Errorf("roles got=%+v", roles)
+	}
+	if len(c.Artists) != 2 || len(c.Roles(1)) != 2 {
+		t.Errorf("artists got=%+v", c.Artists)
+	}
+}
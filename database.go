@@ -1,8 +1,13 @@
 package discogs
 
 import (
+	"context"
+	"net/http"
 	"net/url"
 	"strconv"
+
+	"github.com/gomodule/oauth1/oauth"
+	"go.opencensus.io/trace"
 )
 
 const (
@@ -26,19 +31,36 @@ type DatabaseService interface {
 	Master(masterID int) (*Master, error)
 	// MasterVersions retrieves a list of all Releases that are versions of this master.
 	MasterVersions(masterID int, pagination *Pagination) (*MasterVersions, error)
+	// FilteredMasterVersions retrieves the versions of a master matching filter.
+	FilteredMasterVersions(masterID int, filter VersionFilter, pagination *Pagination) (*MasterVersions, error)
 	// Release returns release by release's ID.
 	Release(releaseID int) (*Release, error)
 	// ReleaseRating retruns community release rating.
 	ReleaseRating(releaseID int) (*ReleaseRating, error)
+	// ReleaseStats returns the number of users having and wanting a release.
+	ReleaseStats(releaseID int) (*ReleaseStats, error)
+	// ReleaseUserRating returns the rating of a release by username,
+	// authenticated with OAuth if options are given.
+	ReleaseUserRating(ctx context.Context, releaseID int, username string, options ...Option) (*UserReleaseRating, error)
+	// SetReleaseUserRating sets the rating of a release by username, from 1 to 5.
+	// Authentication as username is required.
+	SetReleaseUserRating(ctx context.Context, releaseID int, username string, rating int, options ...Option) (*UserReleaseRating, error)
+	// DeleteReleaseUserRating removes the rating of a release by username.
+	// Authentication as username is required.
+	DeleteReleaseUserRating(ctx context.Context, releaseID int, username string, options ...Option) error
 }
 
 type databaseService struct {
-	url      string
-	currency string
+	client      *client
+	url         string
+	currency    string
+	oauthClient *oauth.Client
+	creds       *oauth.Credentials
 }
 
-func newDatabaseService(url string, currency string) DatabaseService {
+func newDatabaseService(c *client, url string, currency string) DatabaseService {
 	return &databaseService{
+		client:   c,
 		url:      url,
 		currency: currency,
 	}
@@ -50,7 +72,7 @@ type Release struct {
 	ID                int            `json:"id"`
 	Artists           []ArtistSource `json:"artists"`
 	ArtistsSort       string         `json:"artists_sort"`
-	DataQuality       string         `json:"data_quality"`
+	DataQuality       DataQuality    `json:"data_quality"`
 	Thumb             string         `json:"thumb"`
 	Community         Community      `json:"community"`
 	Companies         []Company      `json:"companies"`
@@ -74,7 +96,7 @@ type Release struct {
 	ReleasedFormatted string         `json:"released_formatted"`
 	ResourceURL       string         `json:"resource_url"`
 	Series            []Series       `json:"series"`
-	Status            string         `json:"status"`
+	Status            ReleaseStatus  `json:"status"`
 	Styles            []string       `json:"styles"`
 	Tracklist         []Track        `json:"tracklist"`
 	URI               string         `json:"uri"`
@@ -87,7 +109,7 @@ func (s *databaseService) Release(releaseID int) (*Release, error) {
 	params.Set("curr_abbr", s.currency)
 
 	var release *Release
-	err := request(s.url+releasesURI+strconv.Itoa(releaseID), params, &release)
+	err := s.client.request(s.url+releasesURI+strconv.Itoa(releaseID), params, &release)
 	return release, err
 }
 
@@ -99,32 +121,117 @@ type ReleaseRating struct {
 
 func (s *databaseService) ReleaseRating(releaseID int) (*ReleaseRating, error) {
 	var rating *ReleaseRating
-	err := request(s.url+releasesURI+strconv.Itoa(releaseID)+"/rating", nil, &rating)
+	err := s.client.request(s.url+releasesURI+strconv.Itoa(releaseID)+"/rating", nil, &rating)
 	return rating, err
 }
 
+// ReleaseStats is the number of users having and wanting a release.
+type ReleaseStats struct {
+	NumHave     int  `json:"num_have"`
+	NumWant     int  `json:"num_want"`
+	IsOffensive bool `json:"is_offensive"`
+}
+
+func (s *databaseService) ReleaseStats(releaseID int) (*ReleaseStats, error) {
+	var stats *ReleaseStats
+	err := s.client.request(s.url+releasesURI+strconv.Itoa(releaseID)+"/stats", nil, &stats)
+	return stats, err
+}
+
+// UserReleaseRating is the rating of a release by a user.
+type UserReleaseRating struct {
+	Username  string `json:"username"`
+	ReleaseID int    `json:"release_id"`
+	Rating    int    `json:"rating"`
+}
+
+func (s *databaseService) ReleaseUserRating(ctx context.Context, releaseID int, username string, options ...Option) (*UserReleaseRating, error) {
+	return s.userRating(ctx, "ReleaseUserRating", http.MethodGet, releaseID, username, nil, options)
+}
+
+func (s *databaseService) SetReleaseUserRating(ctx context.Context, releaseID int, username string, rating int, options ...Option) (*UserReleaseRating, error) {
+	if rating < 1 || rating > 5 {
+		return nil, ErrInvalidReleaseRating
+	}
+
+	params := url.Values{}
+	params.Set("rating", strconv.Itoa(rating))
+	return s.userRating(ctx, "SetReleaseUserRating", http.MethodPut, releaseID, username, params, options)
+}
+
+func (s *databaseService) DeleteReleaseUserRating(ctx context.Context, releaseID int, username string, options ...Option) error {
+	_, err := s.userRating(ctx, "DeleteReleaseUserRating", http.MethodDelete, releaseID, username, nil, options)
+	return err
+}
+
+func (s *databaseService) userRating(ctx context.Context, name, method string, releaseID int, username string, params url.Values, options []Option) (*UserReleaseRating, error) {
+	ctx, span := trace.StartSpan(ctx, "ninnemana.discogs."+name)
+	defer span.End()
+
+	// options apply to this call only, the service is shared
+	sc := *s
+	for _, opts := range options {
+		opts(&sc)
+	}
+
+	route := sc.url + releasesURI + strconv.Itoa(releaseID) + "/rating/" + username
+
+	span.AddAttributes(
+		trace.StringAttribute("username", username),
+		trace.Int64Attribute("release_id", int64(releaseID)),
+		trace.StringAttribute("route", route),
+	)
+
+	var (
+		rating UserReleaseRating
+		resp   interface{} = &rating
+	)
+	if method == http.MethodDelete {
+		resp = nil
+	}
+
+	var err error
+	if method == http.MethodGet && (sc.oauthClient == nil || sc.creds == nil) {
+		// Reading a rating does not require OAuth.
+		err = sc.client.request(route, params, resp)
+	} else {
+		err = sc.client.sendWithCreds(ctx, method, route, sc.oauthClient, sc.creds, params, resp)
+	}
+	if err != nil {
+		span.SetStatus(trace.Status{
+			Code: trace.StatusCodeInternal,
+		})
+		span.AddAttributes(trace.StringAttribute("err", err.Error()))
+
+		return nil, err
+	}
+
+	return &rating, nil
+}
+
 // Artist resource represents a person in the Discogs database
 // who contributed to a Release in some capacity.
 // More information https://www.discogs.com/developers#page:database,header:database-artist
 type Artist struct {
-	ID             int      `json:"id"`
-	Name           string   `json:"name"`
-	Realname       string   `json:"realname"`
-	Members        []Member `json:"members,omitempty"`
-	Aliases        []Alias  `json:"aliases,omitempty"`
-	Namevariations []string `json:"namevariations"`
-	Images         []Image  `json:"images"`
-	Profile        string   `json:"profile"`
-	ReleasesURL    string   `json:"releases_url"`
-	ResourceURL    string   `json:"resource_url"`
-	URI            string   `json:"uri"`
-	URLs           []string `json:"urls"`
-	DataQuality    string   `json:"data_quality"`
+	ID             int         `json:"id"`
+	Name           string      `json:"name"`
+	Realname       string      `json:"realname"`
+	Members        []Member    `json:"members,omitempty"`
+	Groups         []Group     `json:"groups,omitempty"`
+	Aliases        []Alias     `json:"aliases,omitempty"`
+	Namevariations []string    `json:"namevariations"`
+	Images         []Image     `json:"images"`
+	Profile        string      `json:"profile"`
+	ReleasesURL    string      `json:"releases_url"`
+	ResourceURL    string      `json:"resource_url"`
+	URI            string      `json:"uri"`
+	URLs           []string    `json:"urls"`
+	DataQuality    DataQuality `json:"data_quality"`
 }
 
 func (s *databaseService) Artist(artistID int) (*Artist, error) {
 	var artist *Artist
-	err := request(s.url+artistsURI+strconv.Itoa(artistID), nil, &artist)
+	err := s.client.request(s.url+artistsURI+strconv.Itoa(artistID), nil, &artist)
 	return artist, err
 }
 
@@ -135,30 +242,35 @@ type ArtistReleases struct {
 }
 
 func (s *databaseService) ArtistReleases(artistID int, pagination *Pagination) (*ArtistReleases, error) {
+	if err := pagination.Validate(ArtistReleasesSortKeys); err != nil {
+		return nil, err
+	}
+
 	var releases *ArtistReleases
-	err := request(s.url+artistsURI+strconv.Itoa(artistID)+"/releases", pagination.params(), &releases)
+	err := s.client.request(s.url+artistsURI+strconv.Itoa(artistID)+"/releases", pagination.params(), &releases)
 	return releases, err
 }
 
 // Label resource represents a label, company, recording studio, location,
 // or other entity involved with artists and releases.
 type Label struct {
-	Profile     string     `json:"profile"`
-	ReleasesURL string     `json:"releases_url"`
-	Name        string     `json:"name"`
-	ContactInfo string     `json:"contact_info"`
-	URI         string     `json:"uri"`
-	Sublabels   []Sublable `json:"sublabels"`
-	URLs        []string   `json:"urls"`
-	Images      []Image    `json:"images"`
-	ResourceURL string     `json:"resource_url"`
-	ID          int        `json:"id"`
-	DataQuality string     `json:"data_quality"`
+	Profile     string      `json:"profile"`
+	ReleasesURL string      `json:"releases_url"`
+	Name        string      `json:"name"`
+	ContactInfo string      `json:"contact_info"`
+	URI         string      `json:"uri"`
+	Sublabels   []Sublable  `json:"sublabels"`
+	ParentLabel *LabelRef   `json:"parent_label,omitempty"`
+	URLs        []string    `json:"urls"`
+	Images      []Image     `json:"images"`
+	ResourceURL string      `json:"resource_url"`
+	ID          int         `json:"id"`
+	DataQuality DataQuality `json:"data_quality"`
 }
 
 func (s *databaseService) Label(labelID int) (*Label, error) {
 	var label *Label
-	err := request(s.url+labelsURI+strconv.Itoa(labelID), nil, &label)
+	err := s.client.request(s.url+labelsURI+strconv.Itoa(labelID), nil, &label)
 	return label, err
 }
 
@@ -169,8 +281,12 @@ type LabelReleases struct {
 }
 
 func (s *databaseService) LabelReleases(labelID int, pagination *Pagination) (*LabelReleases, error) {
+	if err := pagination.Validate(LabelReleasesSortKeys); err != nil {
+		return nil, err
+	}
+
 	var releases *LabelReleases
-	err := request(s.url+labelsURI+strconv.Itoa(labelID)+"/releases", pagination.params(), &releases)
+	err := s.client.request(s.url+labelsURI+strconv.Itoa(labelID)+"/releases", pagination.params(), &releases)
 	return releases, err
 }
 
@@ -197,12 +313,12 @@ type Master struct {
 	MostRecentReleaseURL string         `json:"most_recent_release_url"`
 	VersionsURL          string         `json:"versions_url"`
 	ResourceURL          string         `json:"resource_url"`
-	DataQuality          string         `json:"data_quality"`
+	DataQuality          DataQuality    `json:"data_quality"`
 }
 
 func (s *databaseService) Master(masterID int) (*Master, error) {
 	var master *Master
-	err := request(s.url+mastersURI+strconv.Itoa(masterID), nil, &master)
+	err := s.client.request(s.url+mastersURI+strconv.Itoa(masterID), nil, &master)
 	return master, err
 }
 
@@ -213,7 +329,43 @@ type MasterVersions struct {
 }
 
 func (s *databaseService) MasterVersions(masterID int, pagination *Pagination) (*MasterVersions, error) {
+	return s.FilteredMasterVersions(masterID, VersionFilter{}, pagination)
+}
+
+// VersionFilter narrows the versions of a master release.
+// Empty fields do not filter.
+type VersionFilter struct {
+	Format   string // major format, like Vinyl or CD
+	Label    string // label name
+	Released string // release year, like 1975
+	Country  string // release country, like UK
+}
+
+func (f VersionFilter) params(params url.Values) url.Values {
+	if params == nil {
+		params = url.Values{}
+	}
+	if f.Format != "" {
+		params.Set("format", f.Format)
+	}
+	if f.Label != "" {
+		params.Set("label", f.Label)
+	}
+	if f.Released != "" {
+		params.Set("released", f.Released)
+	}
+	if f.Country != "" {
+		params.Set("country", f.Country)
+	}
+	return params
+}
+
+func (s *databaseService) FilteredMasterVersions(masterID int, filter VersionFilter, pagination *Pagination) (*MasterVersions, error) {
+	if err := pagination.Validate(MasterVersionsSortKeys); err != nil {
+		return nil, err
+	}
+
 	var versions *MasterVersions
-	err := request(s.url+mastersURI+strconv.Itoa(masterID)+"/versions", pagination.params(), &versions)
+	err := s.client.request(s.url+mastersURI+strconv.Itoa(masterID)+"/versions", filter.params(pagination.params()), &versions)
 	return versions, err
 }
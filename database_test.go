@@ -1,13 +1,16 @@
 package discogs
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/gomodule/oauth1/oauth"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -106,3 +109,76 @@ func TestDatabaseServiceArtist(t *testing.T) {
 	}
 	compareJson(t, string(json), artistJson)
 }
+
+func RatingServer(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/releases/8138518/rating/someuser" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	signed := strings.HasPrefix(r.Header.Get("Authorization"), "OAuth ")
+	switch r.Method {
+	case http.MethodGet:
+		// the rating is only readable with the token, so that a request
+		// signed by leftover credentials fails
+		if signed {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		io.WriteString(w, `{"username": "someuser", "release_id": 8138518, "rating": 3}`)
+	case http.MethodPut:
+		if !signed {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("rating") != "4" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+		io.WriteString(w, `{"username": "someuser", "release_id": 8138518, "rating": 4}`)
+	case http.MethodDelete:
+		if !signed {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestDatabaseServiceUserRating(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(RatingServer))
+	defer ts.Close()
+
+	d := initDiscogsClient(t, &Options{URL: ts.URL})
+	ctx := context.Background()
+	opts := []Option{
+		WithClient(&oauth.Client{}),
+		WithCredentials(&oauth.Credentials{Token: "token", Secret: "secret"}),
+	}
+
+	if _, err := d.SetReleaseUserRating(ctx, 8138518, "someuser", 0, opts...); err != ErrInvalidReleaseRating {
+		t.Errorf("err got=%v; want=%v", err, ErrInvalidReleaseRating)
+	}
+
+	rating, err := d.SetReleaseUserRating(ctx, 8138518, "someuser", 4, opts...)
+	if err != nil {
+		t.Fatalf("failed to set rating: %s", err)
+	}
+	if rating.Rating != 4 || rating.Username != "someuser" || rating.ReleaseID != 8138518 {
+		t.Errorf("rating got=%+v", rating)
+	}
+
+	if err := d.DeleteReleaseUserRating(ctx, 8138518, "someuser", opts...); err != nil {
+		t.Errorf("failed to delete rating: %s", err)
+	}
+
+	// without options, the rating is read with the token again
+	if rating, err = d.ReleaseUserRating(ctx, 8138518, "someuser"); err != nil || rating.Rating != 3 {
+		t.Errorf("rating got=%+v, %v", rating, err)
+	}
+	if _, err := d.ReleaseUserRating(ctx, 1, "someuser"); err != ErrNotFound {
+		t.Errorf("err got=%v; want=%v", err, ErrNotFound)
+	}
+}
@@ -0,0 +1,229 @@
+package discogs
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DatePrecision is how much of a Date is known.
+type DatePrecision int
+
+// Date precisions.
+const (
+	DatePrecisionNone DatePrecision = iota
+	DatePrecisionYear
+	DatePrecisionMonth
+	DatePrecisionDay
+)
+
+// Date is a partial release date: Discogs releases may only know their year,
+// or their year and month. Unknown parts are zero.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+var datePattern = regexp.MustCompile(`^(\d{4})(?:-(\d{2})(?:-(\d{2}))?)?$`)
+
+// ParseDate parses a release date such as "1996", "1996-11", "1996-00-00"
+// or "1996-11-12"; zero months and days are unknown. An empty date returns the zero Date.
+func ParseDate(s string) (Date, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Date{}, nil
+	}
+
+	m := datePattern.FindStringSubmatch(s)
+	if m == nil {
+		return Date{}, fmt.Errorf("%w %q", ErrInvalidDate, s)
+	}
+
+	var d Date
+	d.Year, _ = strconv.Atoi(m[1])
+	month, _ := strconv.Atoi(m[2])
+	d.Month = time.Month(month)
+	d.Day, _ = strconv.Atoi(m[3])
+
+	switch {
+	case d.Year == 0:
+		return Date{}, fmt.Errorf("%w %q", ErrInvalidDate, s)
+	case d.Month > time.December:
+		return Date{}, fmt.Errorf("%w %q", ErrInvalidDate, s)
+	case d.Month == 0 && d.Day != 0:
+		return Date{}, fmt.Errorf("%w %q", ErrInvalidDate, s)
+	case d.Day != 0 && d.Day > daysIn(d.Year, d.Month):
+		return Date{}, fmt.Errorf("%w %q", ErrInvalidDate, s)
+	}
+	return d, nil
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// Precision returns how much of the date is known.
+func (d Date) Precision() DatePrecision {
+	switch {
+	case d.Year == 0:
+		return DatePrecisionNone
+	case d.Month == 0:
+		return DatePrecisionYear
+	case d.Day == 0:
+		return DatePrecisionMonth
+	default:
+		return DatePrecisionDay
+	}
+}
+
+// IsZero reports whether the date is unknown.
+func (d Date) IsZero() bool {
+	return d.Year == 0
+}
+
+// Compare returns -1, 0 or +1 when d is before, equal to or after o.
+// Unknown dates sort first and, within a year or month, a partial date
+// sorts before the fuller dates it contains.
+func (d Date) Compare(o Date) int {
+	switch {
+	case d.Year != o.Year:
+		return compareInt(d.Year, o.Year)
+	case d.Month != o.Month:
+		return compareInt(int(d.Month), int(o.Month))
+	default:
+		return compareInt(d.Day, o.Day)
+	}
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Before reports whether d sorts before o.
+func (d Date) Before(o Date) bool {
+	return d.Compare(o) < 0
+}
+
+// After reports whether d sorts after o.
+func (d Date) After(o Date) bool {
+	return d.Compare(o) > 0
+}
+
+// Contains reports whether o falls within d, like 1996-11-12 within 1996.
+func (d Date) Contains(o Date) bool {
+	switch d.Precision() {
+	case DatePrecisionYear:
+		return d.Year == o.Year
+	case DatePrecisionMonth:
+		return d.Year == o.Year && d.Month == o.Month
+	case DatePrecisionDay:
+		return d == o
+	default:
+		return false
+	}
+}
+
+// Time returns the first moment of the date in UTC, or the zero time when it is unknown.
+func (d Date) Time() time.Time {
+	if d.IsZero() {
+		return time.Time{}
+	}
+	month, day := d.Month, d.Day
+	if month == 0 {
+		month = time.January
+	}
+	if day == 0 {
+		day = 1
+	}
+	return time.Date(d.Year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// String returns the date as "1996", "1996-11" or "1996-11-12", or "" when it is unknown.
+func (d Date) String() string {
+	switch d.Precision() {
+	case DatePrecisionYear:
+		return fmt.Sprintf("%04d", d.Year)
+	case DatePrecisionMonth:
+		return fmt.Sprintf("%04d-%02d", d.Year, d.Month)
+	case DatePrecisionDay:
+		return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+	default:
+		return ""
+	}
+}
+
+// Format returns the date the way Discogs displays it: "1996", "Nov 1996" or "12 Nov 1996".
+func (d Date) Format() string {
+	switch d.Precision() {
+	case DatePrecisionYear:
+		return strconv.Itoa(d.Year)
+	case DatePrecisionMonth:
+		return d.Time().Format("Jan 2006")
+	case DatePrecisionDay:
+		return d.Time().Format("2 Jan 2006")
+	default:
+		return ""
+	}
+}
+
+// MarshalText encodes the date like String.
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText decodes the date like ParseDate.
+func (d *Date) UnmarshalText(text []byte) error {
+	date, err := ParseDate(string(text))
+	if err != nil {
+		return err
+	}
+	*d = date
+	return nil
+}
+
+// ParseTimestamp parses a date_added or date_changed timestamp,
+// like "2016-02-19T01:49:21-08:00".
+func ParseTimestamp(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02T15:04:05", s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%w %q", ErrInvalidDate, s)
+}
+
+// ReleasedDate returns the parsed release date.
+func (r *Release) ReleasedDate() (Date, error) {
+	return ParseDate(r.Released)
+}
+
+// Added returns the time the release was submitted.
+func (r *Release) Added() (time.Time, error) {
+	return ParseTimestamp(r.DateAdded)
+}
+
+// Changed returns the time the release was last changed.
+func (r *Release) Changed() (time.Time, error) {
+	return ParseTimestamp(r.DateChanged)
+}
+
+// ReleasedDate returns the parsed release date of the version.
+func (v Version) ReleasedDate() (Date, error) {
+	return ParseDate(v.Released)
+}
+
+// Added returns the time the release was added to the collection.
+func (i CollectionItem) Added() (time.Time, error) {
+	return ParseTimestamp(i.DateAdded)
+}
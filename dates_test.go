@@ -0,0 +1,120 @@
+package discogs
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want Date
+		err  error
+	}{
+		"empty":        {"", Date{}, nil},
+		"year":         {"1996", Date{Year: 1996}, nil},
+		"zeroed":       {"1996-00-00", Date{Year: 1996}, nil},
+		"month":        {"1996-11", Date{Year: 1996, Month: time.November}, nil},
+		"zeroed day":   {"1996-11-00", Date{Year: 1996, Month: time.November}, nil},
+		"full":         {"1996-11-12", Date{Year: 1996, Month: time.November, Day: 12}, nil},
+		"leap":         {"1996-02-29", Date{Year: 1996, Month: time.February, Day: 29}, nil},
+		"no leap":      {"1997-02-29", Date{}, ErrInvalidDate},
+		"day no month": {"1996-00-12", Date{}, ErrInvalidDate},
+		"month 13":     {"1996-13", Date{}, ErrInvalidDate},
+		"text":         {"Nov 1996", Date{}, ErrInvalidDate},
+	}
+	for name, tt := range tests {
+		got, err := ParseDate(tt.in)
+		if got != tt.want || !errors.Is(err, tt.err) {
+			t.Errorf("%s: got=%+v, %v; want=%+v, %v", name, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestDateOrdering(t *testing.T) {
+	var dates []Date
+	for _, s := range []string{"1996-11-12", "", "1996", "1995-06", "1996-11", "1996-02-01"} {
+		d, err := ParseDate(s)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %s", s, err)
+		}
+		dates = append(dates, d)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	var got []string
+	for _, d := range dates {
+		got = append(got, d.String())
+	}
+	want := []string{"", "1995-06", "1996", "1996-02-01", "1996-11", "1996-11-12"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("order got=%q; want=%q", got, want)
+		}
+	}
+
+	year := Date{Year: 1996}
+	if !year.Contains(Date{Year: 1996, Month: time.November, Day: 12}) || year.Contains(Date{Year: 1995}) {
+		t.Errorf("year contains failed")
+	}
+}
+
+func TestDateFormat(t *testing.T) {
+	tests := map[Date]string{
+		{}:                                 "",
+		{Year: 1996}:                       "1996",
+		{Year: 1996, Month: time.November}: "Nov 1996",
+		{Year: 2016, Month: time.February, Day: 18}: "18 Feb 2016",
+	}
+	for d, want := range tests {
+		if got := d.Format(); got != want {
+			t.Errorf("%+v: got=%q; want=%q", d, got, want)
+		}
+	}
+}
+
+func TestDateJSON(t *testing.T) {
+	var v struct {
+		Released Date `json:"released"`
+	}
+	if err := json.Unmarshal([]byte(`{"released": "1996-00-00"}`), &v); err != nil {
+		t.Fatalf("failed to decode date: %s", err)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("failed to encode date: %s", err)
+	}
+	if string(b) != `{"released":"1996"}` {
+		t.Errorf("json got=%s", b)
+	}
+}
+
+func TestReleaseDates(t *testing.T) {
+	var r Release
+	if err := json.Unmarshal([]byte(releaseJson), &r); err != nil {
+		t.Fatalf("failed to decode release: %s", err)
+	}
+
+	released, err := r.ReleasedDate()
+	if err != nil || released != (Date{Year: 2016, Month: time.February, Day: 18}) {
+		t.Errorf("released got=%+v, %v", released, err)
+	}
+	if released.Format() != r.ReleasedFormatted {
+		t.Errorf("formatted got=%q; want=%q", released.Format(), r.ReleasedFormatted)
+	}
+
+	added, err := r.Added()
+	if err != nil || !added.Equal(time.Date(2016, time.February, 19, 9, 49, 21, 0, time.UTC)) {
+		t.Errorf("added got=%v, %v", added, err)
+	}
+	changed, err := r.Changed()
+	if err != nil || !changed.After(added) {
+		t.Errorf("changed got=%v, %v", changed, err)
+	}
+	if _, err := ParseTimestamp("yesterday"); !errors.Is(err, ErrInvalidDate) {
+		t.Errorf("timestamp err got=%v", err)
+	}
+}
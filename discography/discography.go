@@ -0,0 +1,245 @@
+// Package discography builds the discography of an artist from the mix of
+// masters and releases listed by the artist releases endpoint.
+//
+// A Builder pages through every release of the artist, groups them by role,
+// replaces masters by their main release, drops duplicates and sorts each
+// role chronologically:
+//
+//	d, err := discography.New(client).Build(ctx, 38661)
+//	if err != nil {
+//		return err
+//	}
+//	for _, e := range d.Role(discography.RoleMain) {
+//		fmt.Println(e.Year, e.Title)
+//	}
+package discography
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ninnemana/go-discogs"
+)
+
+// Client is the part of the discogs client a Builder uses.
+type Client interface {
+	ArtistReleases(artistID int, pagination *discogs.Pagination) (*discogs.ArtistReleases, error)
+	Master(masterID int) (*discogs.Master, error)
+}
+
+// Roles of an artist on a release, in the order of the sections of a discography.
+const (
+	RoleMain              = "Main"
+	RoleRemix             = "Remix"
+	RoleProducer          = "Producer"
+	RoleTrackAppearance   = "TrackAppearance"
+	RoleAppearance        = "Appearance"
+	RoleUnofficialRelease = "UnofficialRelease"
+)
+
+var roleOrder = map[string]int{
+	RoleMain:              1,
+	RoleRemix:             2,
+	RoleProducer:          3,
+	RoleTrackAppearance:   4,
+	RoleAppearance:        5,
+	RoleUnofficialRelease: 6,
+}
+
+// Entry types, as listed by the artist releases endpoint.
+const (
+	TypeMaster  = "master"
+	TypeRelease = "release"
+)
+
+// Entry is a release of a discography.
+type Entry struct {
+	discogs.ReleaseSource
+	// MasterID is the master the release was listed as, or 0 when the
+	// release was listed on its own.
+	MasterID int `json:"master_id,omitempty"`
+}
+
+// Section holds the releases of one role, oldest first.
+type Section struct {
+	Role    string  `json:"role"`
+	Entries []Entry `json:"entries"`
+}
+
+// Discography is the discography of an artist.
+type Discography struct {
+	ArtistID int `json:"artist_id"`
+	// Sections are ordered Main, Remix, Producer, TrackAppearance, Appearance,
+	// UnofficialRelease, then any other role by name.
+	Sections []Section `json:"sections"`
+}
+
+// Role returns the entries of a role.
+func (d *Discography) Role(role string) []Entry {
+	for _, s := range d.Sections {
+		if s.Role == role {
+			return s.Entries
+		}
+	}
+	return nil
+}
+
+// Builder builds discographies.
+type Builder struct {
+	client Client
+
+	// PerPage is the page size of the artist releases (default is 100).
+	PerPage int
+	// ExpandMasters replaces masters by their main release (default is true).
+	ExpandMasters bool
+}
+
+// New returns a Builder fetching with client.
+func New(client Client) *Builder {
+	return &Builder{
+		client:        client,
+		PerPage:       100,
+		ExpandMasters: true,
+	}
+}
+
+// Build builds the discography of an artist. Masters missing their main
+// release or year are fetched; a master that is not found is kept as listed.
+func (b *Builder) Build(ctx context.Context, artistID int) (*Discography, error) {
+	sources, err := b.releases(ctx, artistID)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Discography{ArtistID: artistID}
+	sections := map[string]*Section{}
+	seen := map[string]map[int]int{}
+	for _, src := range sources {
+		e, err := b.entry(ctx, src)
+		if err != nil {
+			return nil, err
+		}
+
+		role := src.Role
+		if role == "" {
+			role = RoleMain
+		}
+		if seen[role] == nil {
+			seen[role] = map[int]int{}
+			sections[role] = &Section{Role: role}
+		}
+		key := e.ID
+		if e.Type == TypeMaster {
+			// unexpanded masters and releases have distinct ID spaces
+			key = -e.ID
+		}
+		entries := sections[role].Entries
+		if i, ok := seen[role][key]; ok {
+			// a release listed on its own and as a master is kept as the master
+			if entries[i].MasterID == 0 {
+				entries[i].MasterID = e.MasterID
+			}
+			continue
+		}
+		seen[role][key] = len(entries)
+		sections[role].Entries = append(entries, e)
+	}
+
+	for _, s := range sections {
+		sortEntries(s.Entries)
+		d.Sections = append(d.Sections, *s)
+	}
+	sort.Slice(d.Sections, func(i, j int) bool {
+		oi, oj := order(d.Sections[i].Role), order(d.Sections[j].Role)
+		if oi != oj {
+			return oi < oj
+		}
+		return d.Sections[i].Role < d.Sections[j].Role
+	})
+	return d, nil
+}
+
+// order ranks a role; unknown roles rank after the known ones.
+func order(role string) int {
+	if o, ok := roleOrder[role]; ok {
+		return o
+	}
+	return len(roleOrder) + 1
+}
+
+// entry turns a listed master into its main release.
+func (b *Builder) entry(ctx context.Context, src discogs.ReleaseSource) (Entry, error) {
+	e := Entry{ReleaseSource: src}
+	if !b.ExpandMasters || src.Type != TypeMaster {
+		return e, nil
+	}
+
+	if src.MainRelease == 0 || src.Year == 0 {
+		if err := ctx.Err(); err != nil {
+			return Entry{}, err
+		}
+		m, err := b.client.Master(src.ID)
+		if errors.Is(err, discogs.ErrNotFound) {
+			return e, nil
+		}
+		if err != nil {
+			return Entry{}, fmt.Errorf("master %d: %w", src.ID, err)
+		}
+		if e.MainRelease == 0 {
+			e.MainRelease = m.MainRelease
+		}
+		if e.Year == 0 {
+			e.Year = m.Year
+		}
+	}
+	if e.MainRelease == 0 {
+		return e, nil
+	}
+
+	e.MasterID = src.ID
+	e.ID = e.MainRelease
+	e.Type = TypeRelease
+	return e, nil
+}
+
+// releases pages through the releases of an artist.
+func (b *Builder) releases(ctx context.Context, artistID int) ([]discogs.ReleaseSource, error) {
+	var out []discogs.ReleaseSource
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp, err := b.client.ArtistReleases(artistID, &discogs.Pagination{
+			Sort:      discogs.SortByYear,
+			SortOrder: discogs.SortAsc,
+			Page:      page,
+			PerPage:   b.PerPage,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("artist %d releases: %w", artistID, err)
+		}
+		out = append(out, resp.Releases...)
+		if page >= resp.Pagination.Pages {
+			return out, nil
+		}
+	}
+}
+
+// sortEntries sorts entries by year, unknown years last, then by title and ID.
+func sortEntries(entries []Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		switch {
+		case a.Year != b.Year && (a.Year == 0 || b.Year == 0):
+			return b.Year == 0
+		case a.Year != b.Year:
+			return a.Year < b.Year
+		case a.Title != b.Title:
+			return a.Title < b.Title
+		default:
+			return a.ID < b.ID
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package discography
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ninnemana/go-discogs"
+	"github.com/ninnemana/go-discogs/discogstest"
+)
+
+func newBuilder(t *testing.T) *Builder {
+	t.Helper()
+	s := discogstest.NewTestServer(t)
+	s.AddMaster(discogs.Master{ID: 10, Title: "Infinite", Year: 1996, MainRelease: 101})
+	s.AddArtist(discogs.Artist{ID: 1, Name: "Eminem"},
+		discogs.ReleaseSource{ID: 20, Type: TypeMaster, Title: "Second Album", Year: 1999, MainRelease: 201, Role: RoleMain},
+		discogs.ReleaseSource{ID: 10, Type: TypeMaster, Title: "Infinite", Role: RoleMain},
+		discogs.ReleaseSource{ID: 101, Type: TypeRelease, Title: "Infinite", Year: 1996, Role: RoleMain},
+		discogs.ReleaseSource{ID: 300, Type: TypeRelease, Title: "Undated Single", Role: RoleMain},
+		discogs.ReleaseSource{ID: 400, Type: TypeRelease, Title: "Remix 12\"", Year: 1998, Role: RoleRemix},
+		discogs.ReleaseSource{ID: 500, Type: TypeRelease, Title: "Compilation", Year: 1997, Role: RoleAppearance},
+		discogs.ReleaseSource{ID: 600, Type: TypeRelease, Title: "Bootleg", Year: 2000, Role: "Bootleg"},
+		discogs.ReleaseSource{ID: 99, Type: TypeMaster, Title: "Deleted Master", Year: 1995, Role: RoleMain},
+	)
+
+	b := New(s.NewClient(t, ""))
+	b.PerPage = 3
+	return b
+}
+
+func TestBuild(t *testing.T) {
+	b := newBuilder(t)
+
+	d, err := b.Build(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("failed to build: %s", err)
+	}
+
+	var roles []string
+	for _, s := range d.Sections {
+		roles = append(roles, s.Role)
+	}
+	wantRoles := []string{RoleMain, RoleRemix, RoleAppearance, "Bootleg"}
+	if len(roles) != len(wantRoles) {
+		t.Fatalf("roles got=%q; want=%q", roles, wantRoles)
+	}
+	for i := range wantRoles {
+		if roles[i] != wantRoles[i] {
+			t.Errorf("roles got=%q; want=%q", roles, wantRoles)
+		}
+	}
+
+	entries := d.Role(RoleMain)
+	want := []struct {
+		id, master, year int
+		typ              string
+	}{
+		{99, 0, 1995, TypeMaster},
+		{101, 10, 1996, TypeRelease},
+		{201, 20, 1999, TypeRelease},
+		{300, 0, 0, TypeRelease},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("main got=%+v", entries)
+	}
+	for i, w := range want {
+		e := entries[i]
+		if e.ID != w.id || e.MasterID != w.master || e.Year != w.year || e.Type != w.typ {
+			t.Errorf("main %d got=%+v; want=%+v", i, e, w)
+		}
+	}
+}
+
+func TestBuildWithoutExpansion(t *testing.T) {
+	b := newBuilder(t)
+	b.ExpandMasters = false
+
+	d, err := b.Build(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("failed to build: %s", err)
+	}
+	if entries := d.Role(RoleMain); len(entries) != 5 {
+		t.Errorf("main got=%+v", entries)
+	}
+}
+
+func TestBuildNotFound(t *testing.T) {
+	b := newBuilder(t)
+
+	if _, err := b.Build(context.Background(), 2); err == nil {
+		t.Errorf("built the discography of a missing artist")
+	}
+}
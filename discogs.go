@@ -25,6 +25,8 @@ type Options struct {
 	UserAgent string
 	// Token provided by discogs (optional).
 	Token string
+	// Client is the HTTP client used for requests (optional, default is http.DefaultClient).
+	Client *http.Client
 }
 
 // Discogs is an interface for making Discogs API requests.
@@ -33,6 +35,7 @@ type Discogs interface {
 	SearchService
 	UserService
 	CollectionService
+	ImageService
 }
 
 type discogs struct {
@@ -40,19 +43,32 @@ type discogs struct {
 	SearchService
 	UserService
 	CollectionService
+	ImageService
 }
 
-var header *http.Header
+// client sends the requests of a discogs API client with the HTTP client
+// and headers of the Options it was created with, so that clients created
+// by several calls to New do not share them.
+type client struct {
+	http   *http.Client
+	header http.Header
+}
 
 // New returns a new discogs API client.
 func New(o *Options) (Discogs, error) {
-	header = &http.Header{}
-
 	if o == nil || o.UserAgent == "" {
 		return nil, ErrUserAgentInvalid
 	}
 
-	header.Add("User-Agent", o.UserAgent)
+	c := &client{
+		http:   http.DefaultClient,
+		header: http.Header{},
+	}
+	c.header.Add("User-Agent", o.UserAgent)
+
+	if o.Client != nil {
+		c.http = o.Client
+	}
 
 	cur, err := currency(o.Currency)
 	if err != nil {
@@ -61,7 +77,7 @@ func New(o *Options) (Discogs, error) {
 
 	// set token, it's required for some queries like search
 	if o.Token != "" {
-		header.Add("Authorization", "Discogs token="+o.Token)
+		c.header.Add("Authorization", "Discogs token="+o.Token)
 	}
 
 	if o.URL == "" {
@@ -69,10 +85,11 @@ func New(o *Options) (Discogs, error) {
 	}
 
 	return discogs{
-		newDatabaseService(o.URL, cur),
-		newSearchService(o.URL + "/database/search"),
-		newUserService(o.URL),
-		newCollectionService(o.URL),
+		newDatabaseService(c, o.URL, cur),
+		newSearchService(c, o.URL+"/database/search"),
+		newUserService(c, o.URL),
+		newCollectionService(c, o.URL),
+		newImageService(c),
 	}, nil
 }
 
@@ -90,15 +107,14 @@ func currency(c string) (string, error) {
 	}
 }
 
-func request(path string, params url.Values, resp interface{}) error {
+func (c *client) request(path string, params url.Values, resp interface{}) error {
 	r, err := http.NewRequest("GET", path+"?"+params.Encode(), nil)
 	if err != nil {
 		return err
 	}
-	r.Header = *header
+	r.Header = c.header.Clone()
 
-	client := &http.Client{}
-	response, err := client.Do(r)
+	response, err := c.http.Do(r)
 	if err != nil {
 		return err
 	}
@@ -108,6 +124,10 @@ func request(path string, params url.Values, resp interface{}) error {
 		switch response.StatusCode {
 		case http.StatusUnauthorized:
 			return ErrUnauthorized
+		case http.StatusNotFound:
+			return ErrNotFound
+		case http.StatusTooManyRequests:
+			return ErrTooManyRequests
 		default:
 			return fmt.Errorf("unknown error: %s", response.Status)
 		}
@@ -121,17 +141,49 @@ func request(path string, params url.Values, resp interface{}) error {
 	return json.Unmarshal(body, &resp)
 }
 
-func requestWithCreds(ctx context.Context, path string, client *oauth.Client, creds *oauth.Credentials, params url.Values, resp interface{}) error {
-	response, err := client.GetContext(ctx, creds, path, params)
+func (c *client) requestWithCreds(ctx context.Context, path string, client *oauth.Client, creds *oauth.Credentials, params url.Values, resp interface{}) error {
+	return c.sendWithCreds(ctx, http.MethodGet, path, client, creds, params, resp)
+}
+
+// sendWithCreds makes an OAuth signed request. params are sent in the query string
+// for GET and in the form encoded body otherwise. Responses without content,
+// like 204 No Content, leave resp untouched. Without a client and credentials
+// it fails with ErrUnauthorized.
+func (c *client) sendWithCreds(ctx context.Context, method, path string, client *oauth.Client, creds *oauth.Credentials, params url.Values, resp interface{}) error {
+	if client == nil || creds == nil {
+		return ErrUnauthorized
+	}
+	if _, ok := ctx.Value(oauth.HTTPClient).(*http.Client); !ok {
+		ctx = context.WithValue(ctx, oauth.HTTPClient, c.http)
+	}
+
+	var (
+		response *http.Response
+		err      error
+	)
+	switch method {
+	case http.MethodPost:
+		response, err = client.PostContext(ctx, creds, path, params)
+	case http.MethodPut:
+		response, err = client.PutContext(ctx, creds, path, params)
+	case http.MethodDelete:
+		response, err = client.DeleteContext(ctx, creds, path, params)
+	default:
+		response, err = client.GetContext(ctx, creds, path, params)
+	}
 	if err != nil {
 		return err
 	}
 	defer response.Body.Close()
 
-	if response.StatusCode != http.StatusOK {
+	if response.StatusCode < 200 || response.StatusCode > 299 {
 		switch response.StatusCode {
 		case http.StatusUnauthorized:
 			return ErrUnauthorized
+		case http.StatusNotFound:
+			return ErrNotFound
+		case http.StatusTooManyRequests:
+			return ErrTooManyRequests
 		default:
 			return fmt.Errorf("unknown error: %s", response.Status)
 		}
@@ -141,6 +193,9 @@ func requestWithCreds(ctx context.Context, path string, client *oauth.Client, cr
 	if err != nil {
 		return err
 	}
+	if len(body) == 0 || resp == nil {
+		return nil
+	}
 
 	return json.Unmarshal(body, &resp)
 }
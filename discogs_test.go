@@ -1,6 +1,8 @@
 package discogs
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -91,3 +93,28 @@ func TestCurrency(t *testing.T) {
 		}
 	}
 }
+
+func TestNewClients(t *testing.T) {
+	var auth []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	first := initDiscogsClient(t, &Options{URL: ts.URL, Token: "first"})
+	second := initDiscogsClient(t, &Options{URL: ts.URL, Token: "second", Client: &http.Client{}})
+
+	for _, d := range []Discogs{first, second, first} {
+		if _, err := d.Release(1); err != nil {
+			t.Fatalf("failed to get release: %s", err)
+		}
+	}
+	want := []string{"Discogs token=first", "Discogs token=second", "Discogs token=first"}
+	for i := range want {
+		if i >= len(auth) || auth[i] != want[i] {
+			t.Errorf("authorization got=%q; want=%q", auth, want)
+			break
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package discogstest_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ninnemana/go-discogs"
+	"github.com/ninnemana/go-discogs/discogstest"
+)
+
+const (
+	testUserAgent = "UnitTestClient/0.0.2"
+	testToken     = "secret-token"
+	releaseJSON   = `{"id": 8138518, "title": "Elephant Riddim", "year": 2016}`
+)
+
+func TestRecorderRecordReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fixtures")
+	if err != nil {
+		t.Fatalf("failed to create fixture dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var auth string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if r.URL.Path != "/releases/8138518" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := io.WriteString(w, releaseJSON); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+
+	rec := discogstest.NewRecorder(dir, discogstest.ModeRecord, nil)
+	d, err := discogs.New(&discogs.Options{
+		URL:       ts.URL,
+		UserAgent: testUserAgent,
+		Token:     testToken,
+		Client:    &http.Client{Transport: rec},
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+
+	release, err := d.Release(8138518)
+	if err != nil {
+		t.Fatalf("failed to record release: %s", err)
+	}
+	if release.Title != "Elephant Riddim" {
+		t.Errorf("title got=%s; want=%s", release.Title, "Elephant Riddim")
+	}
+	if auth != "Discogs token="+testToken {
+		t.Errorf("authorization got=%q; want token to be sent upstream", auth)
+	}
+	ts.Close()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("fixtures got=%v (%v); want 1 file", files, err)
+	}
+	data, err := ioutil.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("failed to read fixture: %s", err)
+	}
+	if strings.Contains(string(data), testToken) {
+		t.Errorf("fixture contains token: %s", data)
+	}
+	if !strings.Contains(string(data), `"title": "Elephant Riddim"`) {
+		t.Errorf("fixture body is not readable JSON: %s", data)
+	}
+
+	rep := discogstest.NewRecorder(dir, discogstest.ModeReplay, nil)
+	d, err = discogs.New(&discogs.Options{
+		URL:       ts.URL,
+		UserAgent: testUserAgent,
+		Client:    &http.Client{Transport: rep},
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+
+	release, err = d.Release(8138518)
+	if err != nil {
+		t.Fatalf("failed to replay release: %s", err)
+	}
+	if release.ID != 8138518 || release.Year != 2016 {
+		t.Errorf("release got=%+v", release)
+	}
+
+	if _, err := d.Release(1); !errors.Is(err, discogstest.ErrFixtureNotFound) {
+		t.Errorf("err got=%v; want=%v", err, discogstest.ErrFixtureNotFound)
+	}
+}
+
+func TestRecorderBinary(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fixtures")
+	if err != nil {
+		t.Fatalf("failed to create fixture dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	image := []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00\x80\xfe")
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write(image)
+	}))
+
+	rec := discogstest.NewRecorder(dir, discogstest.ModeRecord, nil)
+	d, err := discogs.New(&discogs.Options{UserAgent: testUserAgent, Client: &http.Client{Transport: rec}})
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+	if _, err := d.Image(context.Background(), ts.URL+"/cover.jpg"); err != nil {
+		t.Fatalf("failed to record image: %s", err)
+	}
+	ts.Close()
+
+	rep := discogstest.NewRecorder(dir, discogstest.ModeReplay, nil)
+	d, err = discogs.New(&discogs.Options{UserAgent: testUserAgent, Client: &http.Client{Transport: rep}})
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+	got, err := d.Image(context.Background(), ts.URL+"/cover.jpg")
+	if err != nil {
+		t.Fatalf("failed to replay image: %s", err)
+	}
+	if !bytes.Equal(got, image) {
+		t.Errorf("image got=%q; want=%q", got, image)
+	}
+}
+
+func TestFixtureEncoding(t *testing.T) {
+	tests := map[string]struct {
+		contentType string
+		body        string
+		field       string
+	}{
+		"json":         {"application/json; charset=utf-8", `{"id":1,"title":"Infinite"}`, `"body": {`},
+		"text":         {"text/plain", "Not Found\n", `"body_text": "Not Found\n"`},
+		"invalid json": {"application/json", `{"id":`, `"body_text": "{\"id\":"`},
+		"untyped":      {"", "plain", `"body_text": "plain"`},
+		"image":        {"image/jpeg", "\xff\xd8\xff\xe0", `"body_base64": "/9j/4A=="`},
+		"empty":        {"application/json", "", `"status": 204`},
+	}
+	for name, tt := range tests {
+		f := discogstest.Fixture{Method: "GET", URL: "/", Status: 204, Header: http.Header{}, Body: []byte(tt.body)}
+		if tt.contentType != "" {
+			f.Header.Set("Content-Type", tt.contentType)
+		}
+		data, err := json.MarshalIndent(f, "", "  ")
+		if err != nil {
+			t.Fatalf("%s: failed to encode: %s", name, err)
+		}
+		if !strings.Contains(string(data), tt.field) {
+			t.Errorf("%s: fixture got=%s; want %s", name, data, tt.field)
+		}
+
+		var got discogstest.Fixture
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("%s: failed to decode: %s", name, err)
+		}
+		if !bytes.Equal(got.Body, f.Body) && !(len(got.Body) == 0 && len(f.Body) == 0) {
+			t.Errorf("%s: body got=%q; want=%q", name, got.Body, f.Body)
+		}
+	}
+}
@@ -0,0 +1,992 @@
+package discogstest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ninnemana/go-discogs"
+)
+
+const (
+	defaultPerPage = 50
+	maxPerPage     = 100
+)
+
+// Want is an entry of a user's wantlist as served by the fake server.
+type Want struct {
+	ID               int                   `json:"id"`
+	Rating           int                   `json:"rating"`
+	Notes            string                `json:"notes,omitempty"`
+	ResourceURL      string                `json:"resource_url"`
+	BasicInformation discogs.ReleaseSource `json:"basic_information"`
+}
+
+// Server is an in-memory emulation of the Discogs API backed by httptest.Server.
+// Seed it with the Add* methods and point discogs.Options.URL at Server.URL,
+// or use Server.Options.
+//
+// Search and OAuth identity require an Authorization header, like the real API.
+// Searches authenticated with OAuth return the user data of the identity set
+// with SetIdentity.
+// Every response carries the X-Discogs-Ratelimit headers; once RateLimit requests
+// have been served within a minute, the server answers 429 Too Many Requests.
+type Server struct {
+	*httptest.Server
+
+	// RateLimit is the number of requests allowed per minute (default is 60).
+	RateLimit int
+
+	mu             sync.Mutex
+	releases       map[int]*discogs.Release
+	masters        map[int]*discogs.Master
+	versions       map[int][]discogs.Version
+	artists        map[int]*discogs.Artist
+	artistReleases map[int][]discogs.ReleaseSource
+	labels         map[int]*discogs.Label
+	labelReleases  map[int][]discogs.ReleaseSource
+	folders        map[string][]discogs.Folder
+	items          map[string][]discogs.CollectionItem
+	fields         map[string][]discogs.Field
+	wants          map[string][]Want
+	ratings        map[int]map[string]int
+	images         map[string][]byte
+	identity       *discogs.Identity
+	window         time.Time
+	used           int
+}
+
+// NewServer starts and returns a new, empty fake Discogs server.
+// The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		RateLimit:      60,
+		releases:       make(map[int]*discogs.Release),
+		masters:        make(map[int]*discogs.Master),
+		versions:       make(map[int][]discogs.Version),
+		artists:        make(map[int]*discogs.Artist),
+		artistReleases: make(map[int][]discogs.ReleaseSource),
+		labels:         make(map[int]*discogs.Label),
+		labelReleases:  make(map[int][]discogs.ReleaseSource),
+		folders:        make(map[string][]discogs.Folder),
+		items:          make(map[string][]discogs.CollectionItem),
+		fields:         make(map[string][]discogs.Field),
+		wants:          make(map[string][]Want),
+		ratings:        make(map[int]map[string]int),
+		images:         make(map[string][]byte),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// NewTestServer starts a new, empty fake Discogs server that is closed
+// when the test tb ends.
+func NewTestServer(tb testing.TB) *Server {
+	tb.Helper()
+	s := NewServer()
+	tb.Cleanup(s.Close)
+	return s
+}
+
+// Options returns client options pointing at the server.
+func (s *Server) Options() *discogs.Options {
+	return &discogs.Options{
+		URL:       s.URL,
+		UserAgent: "discogstest",
+		Client:    s.Client(),
+	}
+}
+
+// NewClient returns a discogs client pointing at the server, failing tb if it
+// cannot be created. A token other than "" is sent with every request.
+func (s *Server) NewClient(tb testing.TB, token string) discogs.Discogs {
+	tb.Helper()
+	opts := s.Options()
+	opts.Token = token
+	client, err := discogs.New(opts)
+	if err != nil {
+		tb.Fatalf("failed to create client: %s", err)
+	}
+	return client
+}
+
+// AddRelease seeds a release.
+func (s *Server) AddRelease(r discogs.Release) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.ResourceURL == "" {
+		r.ResourceURL = s.URL + "/releases/" + strconv.Itoa(r.ID)
+	}
+	s.releases[r.ID] = &r
+}
+
+// AddMaster seeds a master release and its versions.
+func (s *Server) AddMaster(m discogs.Master, versions ...discogs.Version) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if m.ResourceURL == "" {
+		m.ResourceURL = s.URL + "/masters/" + strconv.Itoa(m.ID)
+	}
+	m.VersionsURL = s.URL + "/masters/" + strconv.Itoa(m.ID) + "/versions"
+	s.masters[m.ID] = &m
+	s.versions[m.ID] = append(s.versions[m.ID], versions...)
+}
+
+// AddArtist seeds an artist and the releases listed on its releases endpoint.
+func (s *Server) AddArtist(a discogs.Artist, releases ...discogs.ReleaseSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if a.ResourceURL == "" {
+		a.ResourceURL = s.URL + "/artists/" + strconv.Itoa(a.ID)
+	}
+	a.ReleasesURL = s.URL + "/artists/" + strconv.Itoa(a.ID) + "/releases"
+	s.artists[a.ID] = &a
+	s.artistReleases[a.ID] = append(s.artistReleases[a.ID], releases...)
+}
+
+// AddLabel seeds a label and the releases listed on its releases endpoint.
+func (s *Server) AddLabel(l discogs.Label, releases ...discogs.ReleaseSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if l.ResourceURL == "" {
+		l.ResourceURL = s.URL + "/labels/" + strconv.Itoa(l.ID)
+	}
+	l.ReleasesURL = s.URL + "/labels/" + strconv.Itoa(l.ID) + "/releases"
+	s.labels[l.ID] = &l
+	s.labelReleases[l.ID] = append(s.labelReleases[l.ID], releases...)
+}
+
+// AddFolder seeds a collection folder of username.
+func (s *Server) AddFolder(username string, f discogs.Folder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if f.ResourceURL == "" {
+		f.ResourceURL = s.URL + "/users/" + username + "/collection/folders/" + strconv.Itoa(f.ID)
+	}
+	s.folders[username] = append(s.folders[username], f)
+}
+
+// AddCollectionItem seeds a release instance in a folder of username's collection.
+// Folder 0 lists the items of every folder.
+func (s *Server) AddCollectionItem(username string, item discogs.CollectionItem) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items[username] = append(s.items[username], item)
+}
+
+// AddCollectionField seeds a custom field of username's collection.
+func (s *Server) AddCollectionField(username string, f discogs.Field) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fields[username] = append(s.fields[username], f)
+}
+
+// AddWant seeds a wantlist entry of username.
+func (s *Server) AddWant(username string, w Want) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if w.ResourceURL == "" {
+		w.ResourceURL = s.URL + "/users/" + username + "/wants/" + strconv.Itoa(w.ID)
+	}
+	s.wants[username] = append(s.wants[username], w)
+}
+
+// AddImage seeds an image served below /images/ and returns its URL.
+// Like the real image server, it requires a User-Agent.
+func (s *Server) AddImage(name string, data []byte) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.images[name] = data
+	return s.URL + "/images/" + name
+}
+
+// SetIdentity sets the identity returned for OAuth-authenticated requests.
+func (s *Server) SetIdentity(id discogs.Identity) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.identity = &id
+}
+
+// ResetRateLimit clears the number of requests counted against RateLimit.
+func (s *Server) ResetRateLimit() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.used = 0
+	s.window = time.Time{}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.rateLimit(w) {
+		writeError(w, http.StatusTooManyRequests, "You are making requests too quickly.")
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	query := r.URL.Query()
+
+	if len(parts) >= 4 && parts[0] == "users" && parts[2] == "collection" {
+		s.collection(w, r, parts[1], parts[3:])
+		return
+	}
+
+	if len(parts) == 4 && parts[0] == "releases" && parts[2] == "rating" {
+		s.userRating(w, r, parts[1], parts[3])
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+		return
+	}
+
+	switch {
+	case len(parts) == 2 && parts[0] == "images":
+		if r.Header.Get("User-Agent") == "" {
+			writeError(w, http.StatusForbidden, "A User-Agent is required.")
+			return
+		}
+		data, ok := s.images[parts[1]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "The requested resource was not found.")
+			return
+		}
+		w.Header().Set("Content-Type", http.DetectContentType(data))
+		w.Write(data)
+	case len(parts) == 2 && parts[0] == "database" && parts[1] == "search":
+		if r.Header.Get("Authorization") == "" {
+			writeError(w, http.StatusUnauthorized, "You must authenticate to access this resource.")
+			return
+		}
+		s.search(w, r)
+	case len(parts) == 2 && parts[0] == "oauth" && parts[1] == "identity":
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "OAuth ") || s.identity == nil {
+			writeError(w, http.StatusUnauthorized, "You must authenticate to access this resource.")
+			return
+		}
+		writeJSON(w, s.identity)
+	case len(parts) == 3 && parts[0] == "users" && parts[2] == "wants":
+		wants := s.wants[parts[1]]
+		start, end, page := s.paginate(r, len(wants))
+		writeJSON(w, struct {
+			Pagination discogs.Page `json:"pagination"`
+			Wants      []Want       `json:"wants"`
+		}{page, wants[start:end]})
+	default:
+		s.database(w, r, parts, query)
+	}
+}
+
+// collection serves the routes below /users/{username}/collection.
+// Writes require OAuth authentication.
+func (s *Server) collection(w http.ResponseWriter, r *http.Request, username string, parts []string) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+		return
+	}
+	if r.Method == http.MethodPost && !strings.HasPrefix(r.Header.Get("Authorization"), "OAuth ") {
+		writeError(w, http.StatusUnauthorized, "You must authenticate to access this resource.")
+		return
+	}
+
+	pathIDs := make([]int, 0, len(parts))
+	for i := 1; i < len(parts); i += 2 {
+		id, err := strconv.Atoi(parts[i])
+		if err != nil {
+			writeError(w, http.StatusNotFound, "The requested resource was not found.")
+			return
+		}
+		pathIDs = append(pathIDs, id)
+	}
+
+	// the route holds a placeholder for every ID, so that a path missing
+	// one matches no route rather than indexing past pathIDs
+	route := r.Method + " " + parts[0]
+	for i := 1; i < len(parts); i++ {
+		if i%2 == 1 {
+			route += "/{id}"
+		} else {
+			route += "/" + parts[i]
+		}
+	}
+
+	switch route {
+	case "GET folders":
+		writeJSON(w, discogs.CollectionResponse{Folders: s.folders[username]})
+	case "POST folders":
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("name") == "" {
+			writeError(w, http.StatusUnprocessableEntity, "Folder name is required.")
+			return
+		}
+		id := 2
+		for _, f := range s.folders[username] {
+			if f.ID >= id {
+				id = f.ID + 1
+			}
+		}
+		f := discogs.Folder{
+			ID:          id,
+			Name:        r.PostForm.Get("name"),
+			ResourceURL: s.URL + "/users/" + username + "/collection/folders/" + strconv.Itoa(id),
+		}
+		s.folders[username] = append(s.folders[username], f)
+		writeCreated(w, f)
+	case "GET fields":
+		writeJSON(w, discogs.CollectionFields{Fields: s.fields[username]})
+	case "GET folders/{id}/releases":
+		var items []discogs.CollectionItem
+		for _, item := range s.items[username] {
+			if pathIDs[0] == 0 || item.FolderID == pathIDs[0] {
+				items = append(items, item)
+			}
+		}
+		start, end, page := s.paginate(r, len(items))
+		writeJSON(w, discogs.CollectionItems{Pagination: page, Releases: items[start:end]})
+	case "POST folders/{id}/releases/{id}":
+		folder := s.folder(username, pathIDs[0])
+		if folder == nil || pathIDs[0] == 0 {
+			writeError(w, http.StatusNotFound, "Folder not found.")
+			return
+		}
+		release, ok := s.releases[pathIDs[1]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Release not found.")
+			return
+		}
+		instanceID := 1
+		for _, item := range s.items[username] {
+			if item.InstanceID >= instanceID {
+				instanceID = item.InstanceID + 1
+			}
+		}
+		s.items[username] = append(s.items[username], discogs.CollectionItem{
+			ID:         release.ID,
+			InstanceID: instanceID,
+			FolderID:   folder.ID,
+			DateAdded:  time.Now().Format(time.RFC3339),
+			BasicInformation: discogs.BasicInformation{
+				ID:          release.ID,
+				MasterID:    release.MasterID,
+				Title:       release.Title,
+				Year:        release.Year,
+				ResourceURL: release.ResourceURL,
+				Thumb:       release.Thumb,
+				Formats:     release.Formats,
+				Labels:      release.Labels,
+				Artists:     release.Artists,
+				Genres:      release.Genres,
+				Styles:      release.Styles,
+			},
+		})
+		folder.Count++
+		writeCreated(w, discogs.Instance{
+			InstanceID:  instanceID,
+			ResourceURL: s.URL + "/users/" + username + "/collection/folders/" + strconv.Itoa(folder.ID) + "/releases/" + strconv.Itoa(release.ID) + "/instances/" + strconv.Itoa(instanceID),
+		})
+	case "POST folders/{id}/releases/{id}/instances/{id}":
+		item := s.item(username, pathIDs[1], pathIDs[2])
+		if item == nil {
+			writeError(w, http.StatusNotFound, "Instance not found.")
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		if v := r.PostForm.Get("rating"); v != "" {
+			rating, err := strconv.Atoi(v)
+			if err != nil || rating < 0 || rating > 5 {
+				writeError(w, http.StatusUnprocessableEntity, "Invalid rating.")
+				return
+			}
+			item.Rating = rating
+		}
+		if v := r.PostForm.Get("folder_id"); v != "" {
+			folderID, err := strconv.Atoi(v)
+			if err != nil || s.folder(username, folderID) == nil {
+				writeError(w, http.StatusNotFound, "Folder not found.")
+				return
+			}
+			item.FolderID = folderID
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case "POST folders/{id}/releases/{id}/instances/{id}/fields/{id}":
+		item := s.item(username, pathIDs[1], pathIDs[2])
+		if item == nil {
+			writeError(w, http.StatusNotFound, "Instance not found.")
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		value := r.PostForm.Get("value")
+		for i := range item.Notes {
+			if item.Notes[i].FieldID == pathIDs[3] {
+				item.Notes[i].Value = value
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		item.Notes = append(item.Notes, discogs.Note{FieldID: pathIDs[3], Value: value})
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusNotFound, "The requested resource was not found.")
+	}
+}
+
+// userRating serves /releases/{id}/rating/{username}.
+// Changing a rating requires OAuth authentication as username.
+func (s *Server) userRating(w http.ResponseWriter, r *http.Request, releaseID, username string) {
+	id, err := strconv.Atoi(releaseID)
+	if err != nil || s.releases[id] == nil {
+		writeError(w, http.StatusNotFound, "Release not found.")
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "OAuth ") || s.identity == nil {
+			writeError(w, http.StatusUnauthorized, "You must authenticate to access this resource.")
+			return
+		}
+		if s.identity.Username != username {
+			writeError(w, http.StatusForbidden, "You don't have permission to access this resource.")
+			return
+		}
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, discogs.UserReleaseRating{Username: username, ReleaseID: id, Rating: s.ratings[id][username]})
+	case http.MethodPut:
+		if err := r.ParseForm(); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		rating, err := strconv.Atoi(r.PostForm.Get("rating"))
+		if err != nil || rating < 1 || rating > 5 {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid rating.")
+			return
+		}
+		if s.ratings[id] == nil {
+			s.ratings[id] = make(map[string]int)
+		}
+		s.ratings[id][username] = rating
+		writeCreated(w, discogs.UserReleaseRating{Username: username, ReleaseID: id, Rating: rating})
+	case http.MethodDelete:
+		delete(s.ratings[id], username)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+	}
+}
+
+func (s *Server) folder(username string, folderID int) *discogs.Folder {
+	for i := range s.folders[username] {
+		if s.folders[username][i].ID == folderID {
+			return &s.folders[username][i]
+		}
+	}
+	return nil
+}
+
+func (s *Server) item(username string, releaseID, instanceID int) *discogs.CollectionItem {
+	for i := range s.items[username] {
+		if item := &s.items[username][i]; item.ID == releaseID && item.InstanceID == instanceID {
+			return item
+		}
+	}
+	return nil
+}
+
+// CollectionItems returns the items of username's collection.
+func (s *Server) CollectionItems(username string) []discogs.CollectionItem {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items := make([]discogs.CollectionItem, len(s.items[username]))
+	copy(items, s.items[username])
+	return items
+}
+
+// Folders returns the collection folders of username.
+func (s *Server) Folders(username string) []discogs.Folder {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	folders := make([]discogs.Folder, len(s.folders[username]))
+	copy(folders, s.folders[username])
+	return folders
+}
+
+func (s *Server) database(w http.ResponseWriter, r *http.Request, parts []string, query url.Values) {
+	if len(parts) < 2 || len(parts) > 3 {
+		writeError(w, http.StatusNotFound, "The requested resource was not found.")
+		return
+	}
+
+	id, err := strconv.Atoi(parts[1])
+	if err != nil {
+		writeError(w, http.StatusNotFound, "The requested resource was not found.")
+		return
+	}
+
+	sub := ""
+	if len(parts) == 3 {
+		sub = parts[2]
+	}
+
+	switch parts[0] + "/" + sub {
+	case "releases/":
+		if release, ok := s.releases[id]; ok {
+			writeJSON(w, release)
+			return
+		}
+		writeError(w, http.StatusNotFound, "Release not found.")
+	case "releases/rating":
+		if release, ok := s.releases[id]; ok {
+			writeJSON(w, discogs.ReleaseRating{ID: id, Rating: release.Community.Rating})
+			return
+		}
+		writeError(w, http.StatusNotFound, "Release not found.")
+	case "releases/stats":
+		if release, ok := s.releases[id]; ok {
+			writeJSON(w, discogs.ReleaseStats{NumHave: release.Community.Have, NumWant: release.Community.Want})
+			return
+		}
+		writeError(w, http.StatusNotFound, "Release not found.")
+	case "masters/":
+		if master, ok := s.masters[id]; ok {
+			writeJSON(w, master)
+			return
+		}
+		writeError(w, http.StatusNotFound, "Master Release not found.")
+	case "masters/versions":
+		if _, ok := s.masters[id]; !ok {
+			writeError(w, http.StatusNotFound, "Master Release not found.")
+			return
+		}
+		var versions []discogs.Version
+		for _, v := range s.versions[id] {
+			if matchesVersion(v, query) {
+				versions = append(versions, v)
+			}
+		}
+		start, end, page := s.paginate(r, len(versions))
+		writeJSON(w, discogs.MasterVersions{Pagination: page, Versions: versions[start:end]})
+	case "artists/":
+		if artist, ok := s.artists[id]; ok {
+			writeJSON(w, artist)
+			return
+		}
+		writeError(w, http.StatusNotFound, "Artist not found.")
+	case "artists/releases":
+		if _, ok := s.artists[id]; !ok {
+			writeError(w, http.StatusNotFound, "Artist not found.")
+			return
+		}
+		releases := sortReleases(s.artistReleases[id], query)
+		start, end, page := s.paginate(r, len(releases))
+		writeJSON(w, discogs.ArtistReleases{Pagination: page, Releases: releases[start:end]})
+	case "labels/":
+		if label, ok := s.labels[id]; ok {
+			writeJSON(w, label)
+			return
+		}
+		writeError(w, http.StatusNotFound, "Label not found.")
+	case "labels/releases":
+		if _, ok := s.labels[id]; !ok {
+			writeError(w, http.StatusNotFound, "Label not found.")
+			return
+		}
+		releases := sortReleases(s.labelReleases[id], query)
+		start, end, page := s.paginate(r, len(releases))
+		writeJSON(w, discogs.LabelReleases{Pagination: page, Releases: releases[start:end]})
+	default:
+		writeError(w, http.StatusNotFound, "The requested resource was not found.")
+	}
+}
+
+// search matches seeded entities against the query parameters supported by
+// discogs.SearchRequest. Text parameters match case-insensitive substrings.
+func (s *Server) search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	// OAuth-authenticated searches carry the user data of the identity.
+	username := ""
+	if strings.HasPrefix(r.Header.Get("Authorization"), "OAuth ") && s.identity != nil {
+		username = s.identity.Username
+	}
+
+	var results []discogs.Result
+	for _, res := range s.results() {
+		if matches(res, query) {
+			if username != "" {
+				res.UserData = s.userData(username, res)
+			}
+			results = append(results, res)
+		}
+	}
+
+	start, end, page := s.paginate(r, len(results))
+	writeJSON(w, discogs.Search{Pagination: page, Results: results[start:end]})
+}
+
+// userData tells whether the release or master of res is in the collection
+// or wantlist of username.
+func (s *Server) userData(username string, res discogs.Result) *discogs.UserData {
+	var data discogs.UserData
+	inRelease := func(releaseID int) bool {
+		switch res.Type {
+		case discogs.SearchTypeRelease:
+			return releaseID == res.ID
+		case discogs.SearchTypeMaster:
+			r, ok := s.releases[releaseID]
+			return ok && r.MasterID == res.ID
+		}
+		return false
+	}
+	for _, item := range s.items[username] {
+		if inRelease(item.ID) {
+			data.InCollection = true
+		}
+	}
+	for _, w := range s.wants[username] {
+		if inRelease(w.ID) {
+			data.InWantlist = true
+		}
+	}
+	return &data
+}
+
+// results returns all seeded entities as search results, ordered by type and ID.
+func (s *Server) results() []discogs.Result {
+	var results []discogs.Result
+
+	for _, id := range ids(s.releases) {
+		r := s.releases[id]
+		res := discogs.Result{
+			Type:        "release",
+			ID:          r.ID,
+			MasterID:    r.MasterID,
+			Title:       discogs.DisplayArtists(r.Artists) + " - " + r.Title,
+			Country:     r.Country,
+			Genre:       r.Genres,
+			Style:       r.Styles,
+			Thumb:       r.Thumb,
+			URI:         r.URI,
+			ResourceURL: r.ResourceURL,
+			Community:   r.Community,
+		}
+		if r.Year != 0 {
+			res.Year = strconv.Itoa(r.Year)
+		}
+		for _, f := range r.Formats {
+			res.Format = append(res.Format, f.Name)
+			res.Format = append(res.Format, f.Descriptions...)
+		}
+		for _, l := range r.Labels {
+			res.Label = append(res.Label, l.Name)
+			if res.Catno == "" {
+				res.Catno = l.Catno
+			}
+		}
+		for _, i := range r.Identifiers {
+			if i.Type == "Barcode" {
+				res.Barcode = append(res.Barcode, i.Value)
+			}
+		}
+		results = append(results, res)
+	}
+
+	for _, id := range ids(s.masters) {
+		m := s.masters[id]
+		res := discogs.Result{
+			Type:        "master",
+			ID:          m.ID,
+			MasterID:    m.ID,
+			Title:       discogs.DisplayArtists(m.Artists) + " - " + m.Title,
+			Genre:       m.Genres,
+			Style:       m.Styles,
+			URI:         m.URI,
+			ResourceURL: m.ResourceURL,
+		}
+		if m.Year != 0 {
+			res.Year = strconv.Itoa(m.Year)
+		}
+		results = append(results, res)
+	}
+
+	for _, id := range ids(s.artists) {
+		a := s.artists[id]
+		results = append(results, discogs.Result{
+			Type:        "artist",
+			ID:          a.ID,
+			Title:       a.Name,
+			URI:         a.URI,
+			ResourceURL: a.ResourceURL,
+		})
+	}
+
+	for _, id := range ids(s.labels) {
+		l := s.labels[id]
+		results = append(results, discogs.Result{
+			Type:        "label",
+			ID:          l.ID,
+			Title:       l.Name,
+			URI:         l.URI,
+			ResourceURL: l.ResourceURL,
+		})
+	}
+
+	return results
+}
+
+func matches(res discogs.Result, query url.Values) bool {
+	if t := query.Get("type"); t != "" && t != string(res.Type) {
+		return false
+	}
+	if q := query.Get("q"); q != "" && !contains(res.Title, q) {
+		return false
+	}
+
+	title := res.Title
+	artist := ""
+	if i := strings.Index(res.Title, " - "); i >= 0 {
+		artist, title = res.Title[:i], res.Title[i+3:]
+	}
+	if res.Type == "artist" {
+		artist = res.Title
+	}
+
+	checks := []struct {
+		param  string
+		values []string
+	}{
+		{"title", []string{res.Title}},
+		{"release_title", []string{title}},
+		{"artist", []string{artist}},
+		{"country", []string{res.Country}},
+		{"genre", res.Genre},
+		{"style", res.Style},
+		{"format", res.Format},
+		{"label", res.Label},
+		{"catno", []string{res.Catno}},
+		{"barcode", res.Barcode},
+	}
+	for _, c := range checks {
+		// Repeated parameters must all match.
+		for _, v := range query[c.param] {
+			if v == "" {
+				continue
+			}
+			found := false
+			for _, value := range c.values {
+				if contains(value, v) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+	}
+
+	if y := query.Get("year"); y != "" {
+		from, to := y, y
+		if i := strings.Index(y, "-"); i >= 0 {
+			from, to = y[:i], y[i+1:]
+		}
+		if res.Year == "" || res.Year < from || res.Year > to {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesVersion reports whether v passes the format, label, released and
+// country filters of the versions endpoint.
+func matchesVersion(v discogs.Version, query url.Values) bool {
+	if f := query.Get("format"); f != "" {
+		found := contains(v.Format, f)
+		for _, mf := range v.MajorFormats {
+			found = found || strings.EqualFold(mf, f)
+		}
+		if !found {
+			return false
+		}
+	}
+	if l := query.Get("label"); l != "" && !contains(v.Label, l) {
+		return false
+	}
+	if y := query.Get("released"); y != "" && !strings.HasPrefix(v.Released, y) {
+		return false
+	}
+	if c := query.Get("country"); c != "" && !strings.EqualFold(v.Country, c) {
+		return false
+	}
+	return true
+}
+
+// rateLimit counts the request and sets the rate limit headers.
+// It reports whether the request is within the limit.
+func (s *Server) rateLimit(w http.ResponseWriter) bool {
+	now := time.Now()
+	if now.Sub(s.window) > time.Minute {
+		s.window = now
+		s.used = 0
+	}
+
+	limit := s.RateLimit
+	if limit <= 0 {
+		limit = 60
+	}
+
+	allowed := s.used < limit
+	if allowed {
+		s.used++
+	}
+
+	w.Header().Set("X-Discogs-Ratelimit", strconv.Itoa(limit))
+	w.Header().Set("X-Discogs-Ratelimit-Used", strconv.Itoa(s.used))
+	w.Header().Set("X-Discogs-Ratelimit-Remaining", strconv.Itoa(limit-s.used))
+	return allowed
+}
+
+// paginate returns the bounds of the requested page within total items
+// and the pagination block describing it.
+func (s *Server) paginate(r *http.Request, total int) (int, int, discogs.Page) {
+	query := r.URL.Query()
+
+	perPage, err := strconv.Atoi(query.Get("per_page"))
+	if err != nil || perPage <= 0 {
+		perPage = defaultPerPage
+	}
+	if perPage > maxPerPage {
+		perPage = maxPerPage
+	}
+
+	pages := (total + perPage - 1) / perPage
+	if pages == 0 {
+		pages = 1
+	}
+
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+
+	start := (page - 1) * perPage
+	if start > total {
+		start = total
+	}
+	end := start + perPage
+	if end > total {
+		end = total
+	}
+
+	p := discogs.Page{
+		Page:    page,
+		Pages:   pages,
+		PerPage: perPage,
+		Items:   total,
+	}
+	if page < pages {
+		p.URLs.Next = pageURL(s.URL, r.URL, page+1, perPage)
+		p.URLs.Last = pageURL(s.URL, r.URL, pages, perPage)
+	}
+	return start, end, p
+}
+
+func pageURL(base string, u *url.URL, page, perPage int) string {
+	query := u.Query()
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", strconv.Itoa(perPage))
+	return base + u.Path + "?" + query.Encode()
+}
+
+func sortReleases(releases []discogs.ReleaseSource, query url.Values) []discogs.ReleaseSource {
+	sorted := make([]discogs.ReleaseSource, len(releases))
+	copy(sorted, releases)
+
+	var less func(i, j int) bool
+	switch query.Get("sort") {
+	case "year":
+		less = func(i, j int) bool { return sorted[i].Year < sorted[j].Year }
+	case "title":
+		less = func(i, j int) bool { return sorted[i].Title < sorted[j].Title }
+	case "format":
+		less = func(i, j int) bool { return sorted[i].Format < sorted[j].Format }
+	default:
+		return sorted
+	}
+
+	if query.Get("sort_order") == "desc" {
+		asc := less
+		less = func(i, j int) bool { return asc(j, i) }
+	}
+	sort.SliceStable(sorted, less)
+	return sorted
+}
+
+// ids returns the keys of a map of seeded entities in ascending order.
+func ids(m interface{}) []int {
+	var keys []int
+	switch t := m.(type) {
+	case map[int]*discogs.Release:
+		for id := range t {
+			keys = append(keys, id)
+		}
+	case map[int]*discogs.Master:
+		for id := range t {
+			keys = append(keys, id)
+		}
+	case map[int]*discogs.Artist:
+		for id := range t {
+			keys = append(keys, id)
+		}
+	case map[int]*discogs.Label:
+		for id := range t {
+			keys = append(keys, id)
+		}
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+func contains(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeCreated(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(struct {
+		Message string `json:"message"`
+	}{message}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,375 @@
+package discogstest_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/gomodule/oauth1/oauth"
+	"github.com/ninnemana/go-discogs"
+	"github.com/ninnemana/go-discogs/discogstest"
+)
+
+func newServer() *discogstest.Server {
+	s := discogstest.NewServer()
+	s.AddArtist(discogs.Artist{ID: 38661, Name: "Eminem"})
+	s.AddRelease(discogs.Release{
+		ID:      3221262,
+		Title:   "Infinite",
+		Year:    1996,
+		Country: "US",
+		Artists: []discogs.ArtistSource{{ID: 38661, Name: "Eminem"}},
+		Formats: []discogs.Format{{Name: "Vinyl", Qty: "1", Descriptions: []string{"LP", "Album"}}},
+		Labels:  []discogs.LabelSource{{ID: 1, Name: "Web Entertainment", Catno: "WEB 0001"}},
+	})
+	s.AddRelease(discogs.Release{
+		ID:      10670860,
+		Title:   "Infinite",
+		Year:    2009,
+		Country: "Europe",
+		Artists: []discogs.ArtistSource{{ID: 38661, Name: "Eminem"}},
+		Formats: []discogs.Format{{Name: "CD", Qty: "1", Descriptions: []string{"Album", "Reissue"}}},
+	})
+	s.AddMaster(discogs.Master{ID: 718441, Title: "Infinite", Year: 1996, MainRelease: 3221262},
+		discogs.Version{ID: 3221262, Title: "Infinite", Country: "US", Released: "1996", MajorFormats: []string{"Vinyl"}},
+		discogs.Version{ID: 10670860, Title: "Infinite", Country: "Europe", Released: "2009", MajorFormats: []string{"CD"}},
+	)
+	s.SetIdentity(discogs.Identity{ID: 1, Username: "someuser"})
+	s.AddFolder("someuser", discogs.Folder{ID: 0, Name: "All", Count: 2})
+	return s
+}
+
+func TestServerDatabase(t *testing.T) {
+	s := newServer()
+	defer s.Close()
+
+	d, err := discogs.New(s.Options())
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+
+	release, err := d.Release(3221262)
+	if err != nil {
+		t.Fatalf("failed to get release: %s", err)
+	}
+	if release.Title != "Infinite" || release.Year != 1996 {
+		t.Errorf("release got=%+v", release)
+	}
+
+	if _, err := d.Release(1); err == nil {
+		t.Errorf("expected error for unknown release")
+	}
+
+	versions, err := d.MasterVersions(718441, &discogs.Pagination{Page: 2, PerPage: 1})
+	if err != nil {
+		t.Fatalf("failed to get versions: %s", err)
+	}
+	if versions.Pagination.Pages != 2 || len(versions.Versions) != 1 || versions.Versions[0].ID != 10670860 {
+		t.Errorf("versions got=%+v", versions)
+	}
+
+	versions, err = d.FilteredMasterVersions(718441, discogs.VersionFilter{Format: "Vinyl", Released: "1996", Country: "us"}, nil)
+	if err != nil {
+		t.Fatalf("failed to get filtered versions: %s", err)
+	}
+	if len(versions.Versions) != 1 || versions.Versions[0].ID != 3221262 {
+		t.Errorf("filtered versions got=%+v", versions.Versions)
+	}
+}
+
+func TestServerSearch(t *testing.T) {
+	s := newServer()
+	defer s.Close()
+
+	d, err := discogs.New(s.Options())
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+	if _, err := d.Search(discogs.SearchRequest{Q: "infinite"}); err != discogs.ErrUnauthorized {
+		t.Errorf("err got=%v; want=%v", err, discogs.ErrUnauthorized)
+	}
+
+	opts := s.Options()
+	opts.Token = "some token"
+	d, err = discogs.New(opts)
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+
+	tests := map[string]struct {
+		req  discogs.SearchRequest
+		want []int
+	}{
+		"query":   {discogs.SearchRequest{Q: "infinite", Type: "release"}, []int{3221262, 10670860}},
+		"country": {discogs.SearchRequest{Artist: "eminem", Country: "US"}, []int{3221262}},
+		"format":  {discogs.SearchRequest{Format: "Reissue"}, []int{10670860}},
+		"catno":   {discogs.SearchRequest{Catno: "WEB 0001"}, []int{3221262}},
+		"formats": {discogs.SearchRequest{Format: "CD", Formats: []string{"Album"}}, []int{10670860}},
+		"years":   {discogs.SearchRequest{Type: "release", Year: "1990-1999"}, []int{3221262}},
+		"master":  {discogs.SearchRequest{Type: "master"}, []int{718441}},
+		"artist":  {discogs.SearchRequest{Type: "artist", Q: "eminem"}, []int{38661}},
+	}
+	for name := range tests {
+		tt := tests[name]
+		t.Run(name, func(t *testing.T) {
+			search, err := d.Search(tt.req)
+			if err != nil {
+				t.Fatalf("failed to search: %s", err)
+			}
+			var got []int
+			for _, r := range search.Results {
+				got = append(got, r.ID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("results got=%v; want=%v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("results got=%v; want=%v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestServerOAuth(t *testing.T) {
+	s := newServer()
+	defer s.Close()
+
+	d, err := discogs.New(s.Options())
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+
+	opts := []discogs.Option{
+		discogs.WithClient(&oauth.Client{}),
+		discogs.WithCredentials(&oauth.Credentials{Token: "token", Secret: "secret"}),
+	}
+
+	id, err := d.OAuthIdentity(context.Background(), opts...)
+	if err != nil {
+		t.Fatalf("failed to get identity: %s", err)
+	}
+	if id.Username != "someuser" {
+		t.Errorf("username got=%s; want=%s", id.Username, "someuser")
+	}
+
+	folders, err := d.GetFolders(context.Background(), id.Username, opts...)
+	if err != nil {
+		t.Fatalf("failed to get folders: %s", err)
+	}
+	if len(folders.Folders) != 1 || folders.Folders[0].Count != 2 {
+		t.Errorf("folders got=%+v", folders.Folders)
+	}
+
+	// The credentials only apply to the calls they were given to.
+	if _, err := d.GetFolders(context.Background(), id.Username); err != discogs.ErrUnauthorized {
+		t.Errorf("err after oauth got=%v; want=%v", err, discogs.ErrUnauthorized)
+	}
+	if _, err := d.GetCollectionFields(context.Background(), id.Username); err != discogs.ErrUnauthorized {
+		t.Errorf("err after oauth got=%v; want=%v", err, discogs.ErrUnauthorized)
+	}
+}
+
+func TestServerOAuthSearch(t *testing.T) {
+	s := newServer()
+	defer s.Close()
+	s.AddCollectionItem("someuser", discogs.CollectionItem{ID: 3221262, InstanceID: 1, FolderID: 1})
+	s.AddWant("someuser", discogstest.Want{ID: 10670860})
+
+	d, err := discogs.New(s.Options())
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+
+	// Without a token or OAuth credentials, search is refused.
+	if _, err := d.SearchContext(context.Background(), discogs.SearchRequest{Q: "infinite"}); err != discogs.ErrUnauthorized {
+		t.Errorf("err got=%v; want=%v", err, discogs.ErrUnauthorized)
+	}
+
+	search, err := d.SearchContext(context.Background(), discogs.SearchRequest{Type: discogs.SearchTypeRelease},
+		discogs.WithClient(&oauth.Client{}),
+		discogs.WithCredentials(&oauth.Credentials{Token: "token", Secret: "secret"}),
+	)
+	if err != nil {
+		t.Fatalf("failed to search: %s", err)
+	}
+	if len(search.Results) != 2 {
+		t.Fatalf("results got=%d; want=2", len(search.Results))
+	}
+	want := map[int]discogs.UserData{
+		3221262:  {InCollection: true},
+		10670860: {InWantlist: true},
+	}
+	for _, r := range search.Results {
+		if r.UserData == nil || *r.UserData != want[r.ID] {
+			t.Errorf("%d user data got=%+v; want=%+v", r.ID, r.UserData, want[r.ID])
+		}
+	}
+
+	// The credentials only apply to the search they were given to.
+	if _, err := d.SearchContext(context.Background(), discogs.SearchRequest{Q: "infinite"}); err != discogs.ErrUnauthorized {
+		t.Errorf("err after oauth got=%v; want=%v", err, discogs.ErrUnauthorized)
+	}
+}
+
+func TestServerUserRating(t *testing.T) {
+	s := newServer()
+	defer s.Close()
+
+	d, err := discogs.New(s.Options())
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+	ctx := context.Background()
+	opts := []discogs.Option{
+		discogs.WithClient(&oauth.Client{}),
+		discogs.WithCredentials(&oauth.Credentials{Token: "token", Secret: "secret"}),
+	}
+
+	if _, err := d.SetReleaseUserRating(ctx, 3221262, "someuser", 6, opts...); err != discogs.ErrInvalidReleaseRating {
+		t.Errorf("err got=%v; want=%v", err, discogs.ErrInvalidReleaseRating)
+	}
+	if _, err := d.SetReleaseUserRating(ctx, 3221262, "otheruser", 4, opts...); err == nil {
+		t.Error("expected an error rating as another user")
+	}
+
+	rating, err := d.SetReleaseUserRating(ctx, 3221262, "someuser", 4, opts...)
+	if err != nil {
+		t.Fatalf("failed to set rating: %s", err)
+	}
+	if rating.Rating != 4 || rating.ReleaseID != 3221262 || rating.Username != "someuser" {
+		t.Errorf("rating got=%+v", rating)
+	}
+
+	// Reading a rating does not require OAuth.
+	if rating, err = d.ReleaseUserRating(ctx, 3221262, "someuser"); err != nil || rating.Rating != 4 {
+		t.Errorf("rating got=%+v, %v; want 4", rating, err)
+	}
+
+	if err := d.DeleteReleaseUserRating(ctx, 3221262, "someuser", opts...); err != nil {
+		t.Fatalf("failed to delete rating: %s", err)
+	}
+	if rating, err = d.ReleaseUserRating(ctx, 3221262, "someuser", opts...); err != nil || rating.Rating != 0 {
+		t.Errorf("rating got=%+v, %v; want 0", rating, err)
+	}
+
+	if _, err := d.ReleaseUserRating(ctx, 1, "someuser", opts...); err != discogs.ErrNotFound {
+		t.Errorf("err got=%v; want=%v", err, discogs.ErrNotFound)
+	}
+}
+
+func TestServerRateLimit(t *testing.T) {
+	s := newServer()
+	defer s.Close()
+	s.RateLimit = 2
+
+	for i := 1; i <= 3; i++ {
+		resp, err := s.Client().Get(s.URL + "/releases/3221262")
+		if err != nil {
+			t.Fatalf("request failed: %s", err)
+		}
+		resp.Body.Close()
+
+		if i <= 2 {
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("#%d status got=%d; want=%d", i, resp.StatusCode, http.StatusOK)
+			}
+			if got := resp.Header.Get("X-Discogs-Ratelimit-Remaining"); got != strconv.Itoa(2-i) {
+				t.Errorf("#%d remaining got=%s; want=%d", i, got, 2-i)
+			}
+			continue
+		}
+		if resp.StatusCode != http.StatusTooManyRequests {
+			t.Errorf("#%d status got=%d; want=%d", i, resp.StatusCode, http.StatusTooManyRequests)
+		}
+	}
+}
+
+func TestServerCollectionWrites(t *testing.T) {
+	s := newServer()
+	defer s.Close()
+
+	d, err := discogs.New(s.Options())
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+	ctx := context.Background()
+	opts := []discogs.Option{
+		discogs.WithClient(&oauth.Client{}),
+		discogs.WithCredentials(&oauth.Credentials{Token: "token", Secret: "secret"}),
+	}
+
+	folder, err := d.CreateFolder(ctx, "someuser", "Ska", opts...)
+	if err != nil {
+		t.Fatalf("failed to create folder: %s", err)
+	}
+	instance, err := d.AddToFolder(ctx, "someuser", folder.ID, 3221262, opts...)
+	if err != nil {
+		t.Fatalf("failed to add release: %s", err)
+	}
+	if err := d.SetInstanceRating(ctx, "someuser", folder.ID, 3221262, instance.InstanceID, 4, opts...); err != nil {
+		t.Fatalf("failed to rate instance: %s", err)
+	}
+
+	// The credentials only apply to the calls they were given to.
+	if _, err := d.CreateFolder(ctx, "someuser", "Dub"); err != discogs.ErrUnauthorized {
+		t.Errorf("create err got=%v; want=%v", err, discogs.ErrUnauthorized)
+	}
+	if _, err := d.AddToFolder(ctx, "someuser", folder.ID, 3221262); err != discogs.ErrUnauthorized {
+		t.Errorf("add err got=%v; want=%v", err, discogs.ErrUnauthorized)
+	}
+	if err := d.SetInstanceRating(ctx, "someuser", folder.ID, 3221262, instance.InstanceID, 1); err != discogs.ErrUnauthorized {
+		t.Errorf("rating err got=%v; want=%v", err, discogs.ErrUnauthorized)
+	}
+	if err := d.SetInstanceField(ctx, "someuser", folder.ID, 3221262, instance.InstanceID, 1, "Mint (M)"); err != discogs.ErrUnauthorized {
+		t.Errorf("field err got=%v; want=%v", err, discogs.ErrUnauthorized)
+	}
+	if n := len(s.Folders("someuser")); n != 2 {
+		t.Errorf("folders got=%d; want=2", n)
+	}
+	if items := s.CollectionItems("someuser"); len(items) != 1 || items[0].Rating != 4 {
+		t.Errorf("items got=%+v", items)
+	}
+}
+
+func TestServerNewClient(t *testing.T) {
+	s := discogstest.NewTestServer(t)
+	s.AddRelease(discogs.Release{ID: 1, Title: "Some Title"})
+
+	if _, err := s.NewClient(t, "").Search(discogs.SearchRequest{Q: "some"}); err != discogs.ErrUnauthorized {
+		t.Errorf("err without token got=%v; want=%v", err, discogs.ErrUnauthorized)
+	}
+	search, err := s.NewClient(t, "token").Search(discogs.SearchRequest{Q: "some"})
+	if err != nil || len(search.Results) != 1 {
+		t.Errorf("search got=%+v, %v", search, err)
+	}
+}
+
+func TestServerCollectionRoutes(t *testing.T) {
+	s := newServer()
+	defer s.Close()
+
+	for _, path := range []string{
+		"/users/someuser/collection/folders/1/releases",
+		"/users/someuser/collection/folders/1/releases/3221262/instances",
+		"/users/someuser/collection/folders/1/releases/3221262/instances/1/fields",
+		"/users/someuser/collection/folders/releases/3221262",
+	} {
+		r, err := http.NewRequest(http.MethodPost, s.URL+path, nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %s", err)
+		}
+		r.Header.Set("Authorization", "OAuth oauth_token=\"token\"")
+		resp, err := s.Client().Do(r)
+		if err != nil {
+			t.Fatalf("request failed: %s", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("%s status got=%d; want=%d", path, resp.StatusCode, http.StatusNotFound)
+		}
+	}
+}
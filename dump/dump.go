@@ -0,0 +1,223 @@
+// Package dump decodes the monthly Discogs data dumps published at
+// https://data.discogs.com into the same types the API client returns.
+//
+// Dumps are decoded one entity at a time, so memory use does not depend on
+// the size of the file. Gzip-compressed dumps (.xml.gz) are detected and
+// decompressed transparently.
+//
+//	d, err := dump.Open("discogs_20200101_releases.xml.gz")
+//	if err != nil {
+//		return err
+//	}
+//	defer d.Close()
+//
+//	for {
+//		release, err := d.Release()
+//		if err == io.EOF {
+//			break
+//		}
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(release.ID, release.Title)
+//	}
+package dump
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ninnemana/go-discogs"
+)
+
+// Element names of the entities stored in each dump.
+const (
+	artistElement  = "artist"
+	labelElement   = "label"
+	masterElement  = "master"
+	releaseElement = "release"
+)
+
+// Decoder reads entities from a Discogs data dump.
+type Decoder struct {
+	d      *xml.Decoder
+	closer []io.Closer
+	depth  int
+}
+
+// NewDecoder returns a decoder reading a dump from r.
+// Gzip-compressed input is decompressed automatically.
+func NewDecoder(r io.Reader) (*Decoder, error) {
+	br := bufio.NewReader(r)
+
+	magic, err := br.Peek(2)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	d := &Decoder{}
+	var src io.Reader = br
+	if len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		d.closer = append(d.closer, gz)
+		src = gz
+	}
+
+	d.d = xml.NewDecoder(src)
+	return d, nil
+}
+
+// Open opens the dump file name for decoding.
+// The caller should call Close when finished.
+func Open(name string) (*Decoder, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := NewDecoder(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	d.closer = append(d.closer, f)
+	return d, nil
+}
+
+// Close releases the resources held by the decoder.
+func (d *Decoder) Close() error {
+	var err error
+	for _, c := range d.closer {
+		if cerr := c.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// Artist returns the next artist of an artists dump, or io.EOF at the end.
+func (d *Decoder) Artist() (*discogs.Artist, error) {
+	var a xmlArtist
+	if err := d.decode(artistElement, &a); err != nil {
+		return nil, err
+	}
+	return a.artist(), nil
+}
+
+// Label returns the next label of a labels dump, or io.EOF at the end.
+func (d *Decoder) Label() (*discogs.Label, error) {
+	var l xmlLabel
+	if err := d.decode(labelElement, &l); err != nil {
+		return nil, err
+	}
+	return l.label(), nil
+}
+
+// Master returns the next master release of a masters dump, or io.EOF at the end.
+func (d *Decoder) Master() (*discogs.Master, error) {
+	var m xmlMaster
+	if err := d.decode(masterElement, &m); err != nil {
+		return nil, err
+	}
+	return m.master(), nil
+}
+
+// Release returns the next release of a releases dump, or io.EOF at the end.
+func (d *Decoder) Release() (*discogs.Release, error) {
+	var r xmlRelease
+	if err := d.decode(releaseElement, &r); err != nil {
+		return nil, err
+	}
+	return r.release(), nil
+}
+
+// Next returns the next entity of any dump as *discogs.Artist, *discogs.Label,
+// *discogs.Master or *discogs.Release, or io.EOF at the end.
+func (d *Decoder) Next() (interface{}, error) {
+	start, err := d.next("")
+	if err != nil {
+		return nil, err
+	}
+
+	switch start.Name.Local {
+	case artistElement:
+		var a xmlArtist
+		if err := d.element(start, &a); err != nil {
+			return nil, err
+		}
+		return a.artist(), nil
+	case labelElement:
+		var l xmlLabel
+		if err := d.element(start, &l); err != nil {
+			return nil, err
+		}
+		return l.label(), nil
+	case masterElement:
+		var m xmlMaster
+		if err := d.element(start, &m); err != nil {
+			return nil, err
+		}
+		return m.master(), nil
+	case releaseElement:
+		var r xmlRelease
+		if err := d.element(start, &r); err != nil {
+			return nil, err
+		}
+		return r.release(), nil
+	default:
+		if err := d.d.Skip(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("dump: unknown entity %q", start.Name.Local)
+	}
+}
+
+func (d *Decoder) decode(name string, v interface{}) error {
+	start, err := d.next(name)
+	if err != nil {
+		return err
+	}
+	return d.element(start, v)
+}
+
+func (d *Decoder) element(start xml.StartElement, v interface{}) error {
+	if err := d.d.DecodeElement(v, &start); err != nil {
+		return fmt.Errorf("dump: failed to decode %s: %w", start.Name.Local, err)
+	}
+	return nil
+}
+
+// next advances to the next entity start element, that is an element one level
+// below the root. If name is not empty, entities with other names are skipped.
+func (d *Decoder) next(name string) (xml.StartElement, error) {
+	for {
+		tok, err := d.d.Token()
+		if err != nil {
+			return xml.StartElement{}, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if d.depth == 0 {
+				d.depth++
+				continue
+			}
+			if name != "" && t.Name.Local != name {
+				if err := d.d.Skip(); err != nil {
+					return xml.StartElement{}, err
+				}
+				continue
+			}
+			return t, nil
+		case xml.EndElement:
+			d.depth--
+		}
+	}
+}
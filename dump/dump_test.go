@@ -0,0 +1,185 @@
+package dump
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ninnemana/go-discogs"
+)
+
+func TestDecoderArtists(t *testing.T) {
+	d, err := Open("testdata/artists.xml")
+	if err != nil {
+		t.Fatalf("failed to open dump: %s", err)
+	}
+	defer d.Close()
+
+	var artists []*discogs.Artist
+	for {
+		a, err := d.Artist()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to decode artist: %s", err)
+		}
+		artists = append(artists, a)
+	}
+
+	if len(artists) != 2 {
+		t.Fatalf("artists got=%d; want=2", len(artists))
+	}
+	if a := artists[0]; a.Name != "The Persuader" || len(a.Aliases) != 2 || a.Aliases[0].ID != 239 || len(a.Images) != 1 ||
+		len(a.Groups) != 1 || a.Groups[0].ID != 1743 {
+		t.Errorf("artist got=%+v", a)
+	}
+	if a := artists[1]; a.Name != "Mr. James Barth & A.D." || len(a.Members) != 2 || a.Members[1].Name != "Cari Lekebusch" {
+		t.Errorf("artist got=%+v", a)
+	}
+}
+
+func TestDecoderLabels(t *testing.T) {
+	d, err := Open("testdata/labels.xml")
+	if err != nil {
+		t.Fatalf("failed to open dump: %s", err)
+	}
+	defer d.Close()
+
+	l, err := d.Label()
+	if err != nil {
+		t.Fatalf("failed to decode label: %s", err)
+	}
+	if l.ID != 1 || l.Name != "Planet E" || len(l.Sublabels) != 2 || l.Sublabels[1].ID != 41841 || l.ParentLabel != nil {
+		t.Errorf("label got=%+v", l)
+	}
+
+	l, err = d.Label()
+	if err != nil {
+		t.Fatalf("failed to decode label: %s", err)
+	}
+	if l.ParentLabel == nil || l.ParentLabel.ID != 1 || l.ParentLabel.Name != "Planet E" {
+		t.Errorf("parent label got=%+v", l.ParentLabel)
+	}
+}
+
+func TestDecoderMasters(t *testing.T) {
+	d, err := Open("testdata/masters.xml")
+	if err != nil {
+		t.Fatalf("failed to open dump: %s", err)
+	}
+	defer d.Close()
+
+	m, err := d.Master()
+	if err != nil {
+		t.Fatalf("failed to decode master: %s", err)
+	}
+	if m.ID != 18500 || m.MainRelease != 155102 || m.Year != 2001 || m.Artists[0].Name != "Samuel L Session" {
+		t.Errorf("master got=%+v", m)
+	}
+	if len(m.Videos) != 1 || m.Videos[0].Duration != 489 || !m.Videos[0].Embed {
+		t.Errorf("videos got=%+v", m.Videos)
+	}
+
+	if _, err := d.Master(); err != io.EOF {
+		t.Errorf("err got=%v; want=%v", err, io.EOF)
+	}
+}
+
+func TestDecoderReleasesGzip(t *testing.T) {
+	d, err := Open("testdata/releases.xml.gz")
+	if err != nil {
+		t.Fatalf("failed to open dump: %s", err)
+	}
+	defer d.Close()
+
+	r, err := d.Release()
+	if err != nil {
+		t.Fatalf("failed to decode release: %s", err)
+	}
+
+	if r.ID != 1 || r.Status != "Accepted" || r.Title != "Stockholm" || r.Year != 1999 || r.MasterID != 5427 {
+		t.Errorf("release got=%+v", r)
+	}
+	if len(r.Labels) != 1 || r.Labels[0].Catno != "SK032" {
+		t.Errorf("labels got=%+v", r.Labels)
+	}
+	if len(r.Formats) != 1 || r.Formats[0].Qty != "2" || r.FormatQuantity != 2 || len(r.Formats[0].Descriptions) != 2 {
+		t.Errorf("formats got=%+v", r.Formats)
+	}
+	if len(r.Identifiers) != 2 || r.Identifiers[1].Value != "7 314653 006028" {
+		t.Errorf("identifiers got=%+v", r.Identifiers)
+	}
+	if len(r.Tracklist) != 4 || r.Tracklist[2].Type != "heading" || r.Tracklist[3].Extraartists[0].Role != "Remix" {
+		t.Errorf("tracklist got=%+v", r.Tracklist)
+	}
+	if len(r.Companies) != 1 || r.Companies[0].EntityTypeName != "Mastered At" {
+		t.Errorf("companies got=%+v", r.Companies)
+	}
+
+	r, err = d.Release()
+	if err != nil {
+		t.Fatalf("failed to decode release: %s", err)
+	}
+	if r.ID != 2 {
+		t.Errorf("id got=%d; want=2", r.ID)
+	}
+
+	if _, err := d.Release(); err != io.EOF {
+		t.Errorf("err got=%v; want=%v", err, io.EOF)
+	}
+}
+
+func TestDecoderNext(t *testing.T) {
+	d, err := NewDecoder(strings.NewReader(`<masters><master id="1"><title>One</title></master><master id="2"><title>Two</title></master></masters>`))
+	if err != nil {
+		t.Fatalf("failed to create decoder: %s", err)
+	}
+
+	var ids []int
+	for {
+		v, err := d.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to decode: %s", err)
+		}
+		m, ok := v.(*discogs.Master)
+		if !ok {
+			t.Fatalf("entity got=%T; want=*discogs.Master", v)
+		}
+		ids = append(ids, m.ID)
+	}
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+		t.Errorf("ids got=%v; want=[1 2]", ids)
+	}
+}
+
+func TestDecoderSubTracks(t *testing.T) {
+	d, err := NewDecoder(strings.NewReader(`<releases><release id="1"><title>Suite</title><tracklist>
+<track><position>A1</position><title>Intro</title><duration>1:00</duration></track>
+<track><position>A2</position><title>Suite</title><sub_tracks>
+<track><position>A2a</position><title>Part One</title><duration>2:00</duration></track>
+<track><position>A2b</position><title>Part Two</title><duration>3:00</duration></track>
+</sub_tracks></track>
+</tracklist></release></releases>`))
+	if err != nil {
+		t.Fatalf("failed to create decoder: %s", err)
+	}
+
+	r, err := d.Release()
+	if err != nil {
+		t.Fatalf("failed to decode release: %s", err)
+	}
+	if len(r.Tracklist) != 2 {
+		t.Fatalf("tracklist got=%+v", r.Tracklist)
+	}
+	index := r.Tracklist[1]
+	if index.Type != discogs.TrackTypeIndex || len(index.SubTracks) != 2 || index.SubTracks[1].Title != "Part Two" {
+		t.Errorf("index got=%+v", index)
+	}
+	if rt := discogs.TracklistRuntime(r.Tracklist); rt.Tracks != 3 || rt.Duration.Minutes() != 6 {
+		t.Errorf("runtime got=%+v", rt)
+	}
+}
@@ -0,0 +1,317 @@
+package dump
+
+import (
+	"strconv"
+
+	"github.com/ninnemana/go-discogs"
+)
+
+// The xml* types mirror the element layout of the dumps.
+// They are converted to the API types once an entity has been decoded.
+
+type xmlImage struct {
+	Height int    `xml:"height,attr"`
+	Width  int    `xml:"width,attr"`
+	Type   string `xml:"type,attr"`
+	URI    string `xml:"uri,attr"`
+	URI150 string `xml:"uri150,attr"`
+}
+
+type xmlRef struct {
+	ID   int    `xml:"id,attr"`
+	Name string `xml:",chardata"`
+}
+
+type xmlArtistCredit struct {
+	ID     int    `xml:"id"`
+	Name   string `xml:"name"`
+	Anv    string `xml:"anv"`
+	Join   string `xml:"join"`
+	Role   string `xml:"role"`
+	Tracks string `xml:"tracks"`
+}
+
+type xmlVideo struct {
+	Duration    int    `xml:"duration,attr"`
+	Embed       bool   `xml:"embed,attr"`
+	Src         string `xml:"src,attr"`
+	Title       string `xml:"title"`
+	Description string `xml:"description"`
+}
+
+type xmlTrack struct {
+	Position     string            `xml:"position"`
+	Title        string            `xml:"title"`
+	Duration     string            `xml:"duration"`
+	Artists      []xmlArtistCredit `xml:"artists>artist"`
+	ExtraArtists []xmlArtistCredit `xml:"extraartists>artist"`
+	SubTracks    []xmlTrack        `xml:"sub_tracks>track"`
+}
+
+type xmlArtist struct {
+	ID             int                 `xml:"id"`
+	Name           string              `xml:"name"`
+	Realname       string              `xml:"realname"`
+	Profile        string              `xml:"profile"`
+	DataQuality    discogs.DataQuality `xml:"data_quality"`
+	URLs           []string            `xml:"urls>url"`
+	Namevariations []string            `xml:"namevariations>name"`
+	Aliases        []xmlRef            `xml:"aliases>name"`
+	Members        []xmlRef            `xml:"members>name"`
+	Groups         []xmlRef            `xml:"groups>name"`
+	Images         []xmlImage          `xml:"images>image"`
+}
+
+type xmlLabel struct {
+	ID          int                 `xml:"id"`
+	Name        string              `xml:"name"`
+	ContactInfo string              `xml:"contactinfo"`
+	Profile     string              `xml:"profile"`
+	DataQuality discogs.DataQuality `xml:"data_quality"`
+	URLs        []string            `xml:"urls>url"`
+	Sublabels   []xmlRef            `xml:"sublabels>label"`
+	ParentLabel *xmlRef             `xml:"parentLabel"`
+	Images      []xmlImage          `xml:"images>image"`
+}
+
+type xmlMaster struct {
+	ID          int                 `xml:"id,attr"`
+	MainRelease int                 `xml:"main_release"`
+	Title       string              `xml:"title"`
+	Year        int                 `xml:"year"`
+	Notes       string              `xml:"notes"`
+	DataQuality discogs.DataQuality `xml:"data_quality"`
+	Artists     []xmlArtistCredit   `xml:"artists>artist"`
+	Genres      []string            `xml:"genres>genre"`
+	Styles      []string            `xml:"styles>style"`
+	Images      []xmlImage          `xml:"images>image"`
+	Videos      []xmlVideo          `xml:"videos>video"`
+}
+
+type xmlRelease struct {
+	ID           int                   `xml:"id,attr"`
+	Status       discogs.ReleaseStatus `xml:"status,attr"`
+	Title        string                `xml:"title"`
+	Country      string                `xml:"country"`
+	Released     string                `xml:"released"`
+	Notes        string                `xml:"notes"`
+	DataQuality  discogs.DataQuality   `xml:"data_quality"`
+	MasterID     int                   `xml:"master_id"`
+	Artists      []xmlArtistCredit     `xml:"artists>artist"`
+	ExtraArtists []xmlArtistCredit     `xml:"extraartists>artist"`
+	Labels       []struct {
+		ID    int    `xml:"id,attr"`
+		Name  string `xml:"name,attr"`
+		Catno string `xml:"catno,attr"`
+	} `xml:"labels>label"`
+	Formats []struct {
+		Name         string   `xml:"name,attr"`
+		Qty          string   `xml:"qty,attr"`
+		Text         string   `xml:"text,attr"`
+		Descriptions []string `xml:"descriptions>description"`
+	} `xml:"formats>format"`
+	Genres      []string   `xml:"genres>genre"`
+	Styles      []string   `xml:"styles>style"`
+	Tracklist   []xmlTrack `xml:"tracklist>track"`
+	Identifiers []struct {
+		Type        string `xml:"type,attr"`
+		Value       string `xml:"value,attr"`
+		Description string `xml:"description,attr"`
+	} `xml:"identifiers>identifier"`
+	Companies []struct {
+		ID             int    `xml:"id"`
+		Name           string `xml:"name"`
+		Catno          string `xml:"catno"`
+		EntityType     string `xml:"entity_type"`
+		EntityTypeName string `xml:"entity_type_name"`
+		ResourceURL    string `xml:"resource_url"`
+	} `xml:"companies>company"`
+	Images []xmlImage `xml:"images>image"`
+	Videos []xmlVideo `xml:"videos>video"`
+}
+
+func (a *xmlArtist) artist() *discogs.Artist {
+	artist := &discogs.Artist{
+		ID:             a.ID,
+		Name:           a.Name,
+		Realname:       a.Realname,
+		Profile:        a.Profile,
+		DataQuality:    a.DataQuality,
+		URLs:           a.URLs,
+		Namevariations: a.Namevariations,
+		Images:         images(a.Images),
+	}
+	for _, al := range a.Aliases {
+		artist.Aliases = append(artist.Aliases, discogs.Alias{ID: al.ID, Name: al.Name})
+	}
+	for _, m := range a.Members {
+		artist.Members = append(artist.Members, discogs.Member{ID: m.ID, Name: m.Name})
+	}
+	for _, g := range a.Groups {
+		artist.Groups = append(artist.Groups, discogs.Group{ID: g.ID, Name: g.Name})
+	}
+	return artist
+}
+
+func (l *xmlLabel) label() *discogs.Label {
+	label := &discogs.Label{
+		ID:          l.ID,
+		Name:        l.Name,
+		ContactInfo: l.ContactInfo,
+		Profile:     l.Profile,
+		DataQuality: l.DataQuality,
+		URLs:        l.URLs,
+		Images:      images(l.Images),
+	}
+	for _, s := range l.Sublabels {
+		label.Sublabels = append(label.Sublabels, discogs.Sublable{ID: s.ID, Name: s.Name})
+	}
+	if l.ParentLabel != nil {
+		label.ParentLabel = &discogs.LabelRef{ID: l.ParentLabel.ID, Name: l.ParentLabel.Name}
+	}
+	return label
+}
+
+func (m *xmlMaster) master() *discogs.Master {
+	return &discogs.Master{
+		ID:          m.ID,
+		MainRelease: m.MainRelease,
+		Title:       m.Title,
+		Year:        m.Year,
+		Notes:       m.Notes,
+		DataQuality: m.DataQuality,
+		Artists:     credits(m.Artists),
+		Genres:      m.Genres,
+		Styles:      m.Styles,
+		Images:      images(m.Images),
+		Videos:      videos(m.Videos),
+	}
+}
+
+func (r *xmlRelease) release() *discogs.Release {
+	release := &discogs.Release{
+		ID:           r.ID,
+		Status:       r.Status,
+		Title:        r.Title,
+		Country:      r.Country,
+		Released:     r.Released,
+		Notes:        r.Notes,
+		DataQuality:  r.DataQuality,
+		MasterID:     r.MasterID,
+		Artists:      credits(r.Artists),
+		ExtraArtists: credits(r.ExtraArtists),
+		Genres:       r.Genres,
+		Styles:       r.Styles,
+		Tracklist:    tracks(r.Tracklist),
+		Images:       images(r.Images),
+		Videos:       videos(r.Videos),
+	}
+
+	if len(r.Released) >= 4 {
+		release.Year, _ = strconv.Atoi(r.Released[:4])
+	}
+
+	for _, l := range r.Labels {
+		release.Labels = append(release.Labels, discogs.LabelSource{
+			ID:    l.ID,
+			Name:  l.Name,
+			Catno: l.Catno,
+		})
+	}
+	for _, f := range r.Formats {
+		release.Formats = append(release.Formats, discogs.Format{
+			Name:         f.Name,
+			Qty:          f.Qty,
+			Text:         f.Text,
+			Descriptions: f.Descriptions,
+		})
+		if qty, err := strconv.Atoi(f.Qty); err == nil {
+			release.FormatQuantity += qty
+		}
+	}
+	for _, i := range r.Identifiers {
+		release.Identifiers = append(release.Identifiers, discogs.Identifier{
+			Type:        i.Type,
+			Value:       i.Value,
+			Description: i.Description,
+		})
+	}
+	for _, c := range r.Companies {
+		release.Companies = append(release.Companies, discogs.Company{
+			ID:             c.ID,
+			Name:           c.Name,
+			Catno:          c.Catno,
+			EntityType:     c.EntityType,
+			EntityTypeName: c.EntityTypeName,
+			ResourceURL:    c.ResourceURL,
+		})
+	}
+	return release
+}
+
+func credits(in []xmlArtistCredit) []discogs.ArtistSource {
+	var out []discogs.ArtistSource
+	for _, a := range in {
+		out = append(out, discogs.ArtistSource{
+			ID:     a.ID,
+			Name:   a.Name,
+			Anv:    a.Anv,
+			Join:   a.Join,
+			Role:   a.Role,
+			Tracks: a.Tracks,
+		})
+	}
+	return out
+}
+
+// tracks converts a tracklist; the sub tracks of an index track are nested in
+// its SubTracks, like in the API tracklist.
+func tracks(in []xmlTrack) []discogs.Track {
+	var out []discogs.Track
+	for _, t := range in {
+		track := discogs.Track{
+			Position:     t.Position,
+			Title:        t.Title,
+			Duration:     t.Duration,
+			Artists:      credits(t.Artists),
+			Extraartists: credits(t.ExtraArtists),
+			Type:         "track",
+		}
+		if len(t.SubTracks) > 0 {
+			track.Type = "index"
+			track.SubTracks = tracks(t.SubTracks)
+		} else if t.Position == "" {
+			track.Type = "heading"
+		}
+		out = append(out, track)
+	}
+	return out
+}
+
+func images(in []xmlImage) []discogs.Image {
+	var out []discogs.Image
+	for _, i := range in {
+		out = append(out, discogs.Image{
+			Height: i.Height,
+			Width:  i.Width,
+			Type:   i.Type,
+			URI:    i.URI,
+			URI150: i.URI150,
+		})
+	}
+	return out
+}
+
+func videos(in []xmlVideo) []discogs.Video {
+	var out []discogs.Video
+	for _, v := range in {
+		out = append(out, discogs.Video{
+			Duration:    v.Duration,
+			Embed:       v.Embed,
+			URI:         v.Src,
+			Title:       v.Title,
+			Description: v.Description,
+		})
+	}
+	return out
+}
@@ -0,0 +1,181 @@
+package discogs
+
+import (
+	"fmt"
+)
+
+// SearchType is the type of entity a search returns.
+type SearchType string
+
+// Search types.
+const (
+	SearchTypeRelease SearchType = "release"
+	SearchTypeMaster  SearchType = "master"
+	SearchTypeArtist  SearchType = "artist"
+	SearchTypeLabel   SearchType = "label"
+)
+
+// Valid reports whether t is a known search type. An empty type searches all types.
+func (t SearchType) Valid() bool {
+	switch t {
+	case "", SearchTypeRelease, SearchTypeMaster, SearchTypeArtist, SearchTypeLabel:
+		return true
+	}
+	return false
+}
+
+// SortKey is the key a paginated list is sorted by.
+// Every endpoint accepts its own set of keys.
+type SortKey string
+
+// Sort keys.
+const (
+	SortByYear     SortKey = "year"
+	SortByTitle    SortKey = "title"
+	SortByFormat   SortKey = "format"
+	SortByReleased SortKey = "released"
+	SortByLabel    SortKey = "label"
+	SortByCatno    SortKey = "catno"
+	SortByCountry  SortKey = "country"
+	SortByArtist   SortKey = "artist"
+	SortByRating   SortKey = "rating"
+	SortByAdded    SortKey = "added"
+)
+
+// Sort keys accepted by each endpoint.
+var (
+	ArtistReleasesSortKeys  = []SortKey{SortByYear, SortByTitle, SortByFormat}
+	LabelReleasesSortKeys   = []SortKey{SortByYear, SortByTitle, SortByFormat}
+	MasterVersionsSortKeys  = []SortKey{SortByReleased, SortByTitle, SortByFormat, SortByLabel, SortByCatno, SortByCountry}
+	CollectionItemsSortKeys = []SortKey{SortByLabel, SortByArtist, SortByTitle, SortByCatno, SortByFormat, SortByRating, SortByAdded, SortByYear}
+)
+
+// SortOrder is the order of a sorted list.
+type SortOrder string
+
+// Sort orders.
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
+// Valid reports whether o is a known sort order. An empty order is the endpoint default.
+func (o SortOrder) Valid() bool {
+	return o == "" || o == SortAsc || o == SortDesc
+}
+
+// DataQuality is the data quality of a release, master, artist or label,
+// as voted by the community.
+type DataQuality string
+
+// Data quality values.
+const (
+	DataQualityNeedsVote             DataQuality = "Needs Vote"
+	DataQualityCompleteAndCorrect    DataQuality = "Complete and Correct"
+	DataQualityCorrect               DataQuality = "Correct"
+	DataQualityNeedsMinorChanges     DataQuality = "Needs Minor Changes"
+	DataQualityNeedsMajorChanges     DataQuality = "Needs Major Changes"
+	DataQualityEntirelyIncorrect     DataQuality = "Entirely Incorrect"
+	DataQualityEntirelyIncorrectEdit DataQuality = "Entirely Incorrect Edit"
+)
+
+// Valid reports whether q is a known data quality.
+func (q DataQuality) Valid() bool {
+	switch q {
+	case DataQualityNeedsVote, DataQualityCompleteAndCorrect, DataQualityCorrect,
+		DataQualityNeedsMinorChanges, DataQualityNeedsMajorChanges,
+		DataQualityEntirelyIncorrect, DataQualityEntirelyIncorrectEdit:
+		return true
+	}
+	return false
+}
+
+// ReleaseStatus is the status of a release submission.
+type ReleaseStatus string
+
+// Release statuses.
+const (
+	ReleaseStatusAccepted ReleaseStatus = "Accepted"
+	ReleaseStatusDraft    ReleaseStatus = "Draft"
+	ReleaseStatusDeleted  ReleaseStatus = "Deleted"
+	ReleaseStatusRejected ReleaseStatus = "Rejected"
+)
+
+// Valid reports whether s is a known release status.
+func (s ReleaseStatus) Valid() bool {
+	switch s {
+	case ReleaseStatusAccepted, ReleaseStatusDraft, ReleaseStatusDeleted, ReleaseStatusRejected:
+		return true
+	}
+	return false
+}
+
+// maxPerPage is the largest page size the API returns.
+const maxPerPage = 100
+
+// Validate checks the search type, year and pagination of the request, and
+// that release filters are not combined with an artist or label search.
+func (r *SearchRequest) Validate() error {
+	if r == nil {
+		return nil
+	}
+	if !r.Type.Valid() {
+		return fmt.Errorf("%w %q", ErrInvalidSearchType, r.Type)
+	}
+	if r.Type == SearchTypeArtist || r.Type == SearchTypeLabel {
+		if f := r.releaseFilter(); f != "" {
+			return fmt.Errorf("%w: %s with type %q", ErrInvalidSearchFilter, f, r.Type)
+		}
+	}
+	if r.Year != "" && !validYear(r.Year) {
+		return fmt.Errorf("%w %q", ErrInvalidYear, r.Year)
+	}
+	if r.Page < 0 || r.PerPage < 0 || r.PerPage > maxPerPage {
+		return fmt.Errorf("%w: page %d, per page %d", ErrInvalidPagination, r.Page, r.PerPage)
+	}
+	return nil
+}
+
+// releaseFilter returns the name of the first filter of the request that
+// only matches releases and masters, or "" when none is set.
+func (r *SearchRequest) releaseFilter() string {
+	switch {
+	case r.Catno != "":
+		return "catno"
+	case r.Barcode != "":
+		return "barcode"
+	case r.Format != "" || len(r.Formats) > 0:
+		return "format"
+	case r.Label != "":
+		return "label"
+	case r.Year != "":
+		return "year"
+	}
+	return ""
+}
+
+// Validate checks that the page numbers are in range and that the sort
+// key is one of keys. A nil pagination is valid.
+func (p *Pagination) Validate(keys []SortKey) error {
+	if p == nil {
+		return nil
+	}
+	if p.Page < 0 || p.PerPage < 0 || p.PerPage > maxPerPage {
+		return fmt.Errorf("%w: page %d, per page %d", ErrInvalidPagination, p.Page, p.PerPage)
+	}
+	if !p.SortOrder.Valid() {
+		return fmt.Errorf("%w %q", ErrInvalidSortOrder, p.SortOrder)
+	}
+	if p.SortOrder != "" && p.Sort == "" {
+		return fmt.Errorf("%w: sort order without sort key", ErrInvalidSortOrder)
+	}
+	if p.Sort == "" {
+		return nil
+	}
+	for _, k := range keys {
+		if p.Sort == k {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w %q", ErrInvalidSortKey, p.Sort)
+}
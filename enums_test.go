@@ -0,0 +1,114 @@
+package discogs
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSearchRequestValidate(t *testing.T) {
+	tests := map[string]struct {
+		req  SearchRequest
+		want error
+	}{
+		"empty":    {SearchRequest{}, nil},
+		"release":  {SearchRequest{Type: SearchTypeRelease, PerPage: 100}, nil},
+		"typo":     {SearchRequest{Type: "releases"}, ErrInvalidSearchType},
+		"per page": {SearchRequest{PerPage: 101}, ErrInvalidPagination},
+		"page":     {SearchRequest{Page: -1}, ErrInvalidPagination},
+
+		"release filters":   {SearchRequest{Type: SearchTypeRelease, Catno: "WEB 0001", Label: "Web", Year: "1996"}, nil},
+		"master filters":    {SearchRequest{Type: SearchTypeMaster, Format: "Vinyl", Year: "1996"}, nil},
+		"untyped filters":   {SearchRequest{Barcode: "123", Formats: []string{"LP"}}, nil},
+		"artist query":      {SearchRequest{Type: SearchTypeArtist, Q: "Eminem", Country: "US"}, nil},
+		"artist catno":      {SearchRequest{Type: SearchTypeArtist, Catno: "WEB 0001"}, ErrInvalidSearchFilter},
+		"artist barcode":    {SearchRequest{Type: SearchTypeArtist, Barcode: "123"}, ErrInvalidSearchFilter},
+		"artist format":     {SearchRequest{Type: SearchTypeArtist, Format: "Vinyl"}, ErrInvalidSearchFilter},
+		"artist formats":    {SearchRequest{Type: SearchTypeArtist, Formats: []string{"LP"}}, ErrInvalidSearchFilter},
+		"artist label":      {SearchRequest{Type: SearchTypeArtist, Label: "Web"}, ErrInvalidSearchFilter},
+		"label year":        {SearchRequest{Type: SearchTypeLabel, Year: "1996"}, ErrInvalidSearchFilter},
+		"label catno":       {SearchRequest{Type: SearchTypeLabel, Catno: "WEB 0001"}, ErrInvalidSearchFilter},
+		"invalid type year": {SearchRequest{Type: "artists", Year: "1996"}, ErrInvalidSearchType},
+	}
+	for name, tt := range tests {
+		if err := tt.req.Validate(); !errors.Is(err, tt.want) {
+			t.Errorf("%s: got=%v; want=%v", name, err, tt.want)
+		}
+	}
+}
+
+func TestPaginationValidate(t *testing.T) {
+	tests := map[string]struct {
+		p    *Pagination
+		want error
+	}{
+		"nil":        {nil, nil},
+		"sorted":     {&Pagination{Sort: SortByYear, SortOrder: SortDesc}, nil},
+		"wrong key":  {&Pagination{Sort: SortByReleased}, ErrInvalidSortKey},
+		"order":      {&Pagination{Sort: SortByYear, SortOrder: "down"}, ErrInvalidSortOrder},
+		"order only": {&Pagination{SortOrder: SortAsc}, ErrInvalidSortOrder},
+		"per page":   {&Pagination{PerPage: 500}, ErrInvalidPagination},
+	}
+	for name, tt := range tests {
+		if err := tt.p.Validate(ArtistReleasesSortKeys); !errors.Is(err, tt.want) {
+			t.Errorf("%s: got=%v; want=%v", name, err, tt.want)
+		}
+	}
+}
+
+func TestPaginationValidateEndpoints(t *testing.T) {
+	tests := map[string]struct {
+		keys []SortKey
+		sort SortKey
+		want error
+	}{
+		"artist releases year":     {ArtistReleasesSortKeys, SortByYear, nil},
+		"artist releases released": {ArtistReleasesSortKeys, SortByReleased, ErrInvalidSortKey},
+		"artist releases rating":   {ArtistReleasesSortKeys, SortByRating, ErrInvalidSortKey},
+		"label releases format":    {LabelReleasesSortKeys, SortByFormat, nil},
+		"label releases catno":     {LabelReleasesSortKeys, SortByCatno, ErrInvalidSortKey},
+		"master versions released": {MasterVersionsSortKeys, SortByReleased, nil},
+		"master versions country":  {MasterVersionsSortKeys, SortByCountry, nil},
+		"master versions year":     {MasterVersionsSortKeys, SortByYear, ErrInvalidSortKey},
+		"master versions added":    {MasterVersionsSortKeys, SortByAdded, ErrInvalidSortKey},
+		"collection added":         {CollectionItemsSortKeys, SortByAdded, nil},
+		"collection rating":        {CollectionItemsSortKeys, SortByRating, nil},
+		"collection released":      {CollectionItemsSortKeys, SortByReleased, ErrInvalidSortKey},
+		"collection country":       {CollectionItemsSortKeys, SortByCountry, ErrInvalidSortKey},
+	}
+	for name, tt := range tests {
+		p := &Pagination{Sort: tt.sort, SortOrder: SortAsc}
+		if err := p.Validate(tt.keys); !errors.Is(err, tt.want) {
+			t.Errorf("%s: got=%v; want=%v", name, err, tt.want)
+		}
+	}
+}
+
+func TestValidateBeforeRequest(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer ts.Close()
+
+	d := initDiscogsClient(t, &Options{URL: ts.URL})
+	if _, err := d.Search(SearchRequest{Type: "releases"}); !errors.Is(err, ErrInvalidSearchType) {
+		t.Errorf("search error got=%v; want=%v", err, ErrInvalidSearchType)
+	}
+	if _, err := d.MasterVersions(1, &Pagination{Sort: SortByYear}); !errors.Is(err, ErrInvalidSortKey) {
+		t.Errorf("versions error got=%v; want=%v", err, ErrInvalidSortKey)
+	}
+	if requests != 0 {
+		t.Errorf("requests got=%d; want=0", requests)
+	}
+}
+
+func TestEnumsValid(t *testing.T) {
+	if !DataQualityCompleteAndCorrect.Valid() || DataQuality("complete").Valid() {
+		t.Error("unexpected data quality validity")
+	}
+	if !ReleaseStatusAccepted.Valid() || ReleaseStatus("").Valid() {
+		t.Error("unexpected release status validity")
+	}
+}
@@ -17,6 +17,19 @@ func (e *Error) Error() string {
 // APIErrors
 var (
 	ErrUnauthorized         = &Error{"authentication required"}
+	ErrNotFound             = &Error{"resource not found"}
+	ErrTooManyRequests      = &Error{"too many requests"}
 	ErrCurrencyNotSupported = &Error{"currency does not supported"}
 	ErrUserAgentInvalid     = &Error{"invalid user-agent"}
+	ErrInvalidRating        = &Error{"rating must be between 0 and 5"}
+	ErrInvalidReleaseRating = &Error{"rating must be between 1 and 5"}
+	ErrInvalidSearchType    = &Error{"invalid search type"}
+	ErrInvalidSearchFilter  = &Error{"invalid search filter"}
+	ErrInvalidSortKey       = &Error{"invalid sort key"}
+	ErrInvalidSortOrder     = &Error{"invalid sort order"}
+	ErrInvalidPagination    = &Error{"invalid pagination"}
+	ErrInvalidYear          = &Error{"invalid year"}
+	ErrInvalidDuration      = &Error{"invalid track duration"}
+	ErrInvalidPosition      = &Error{"invalid track position"}
+	ErrInvalidDate          = &Error{"invalid date"}
 )
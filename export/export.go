@@ -0,0 +1,408 @@
+// Package export writes a user's Discogs collection as CSV, JSON Lines or a
+// flattened spreadsheet-friendly CSV.
+//
+// Items are written while the collection is paged through, so the output of
+// large collections is never held in memory.
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ninnemana/go-discogs"
+)
+
+// Format is an output format of the exporter.
+type Format int
+
+const (
+	// CSV matches the columns of the collection export on discogs.com.
+	CSV Format = iota
+	// JSONLines writes one JSON encoded Item per line.
+	JSONLines
+	// Flat writes one CSV row per item with every field in its own column
+	// and multiple values separated by "; ".
+	Flat
+)
+
+// ParseFormat returns the format named "csv", "jsonl" or "flat".
+func ParseFormat(s string) (Format, error) {
+	switch s {
+	case "csv":
+		return CSV, nil
+	case "jsonl":
+		return JSONLines, nil
+	case "flat":
+		return Flat, nil
+	default:
+		return 0, fmt.Errorf("export: unknown format %q", s)
+	}
+}
+
+// Client is the part of the discogs client used by the exporter.
+type Client interface {
+	discogs.CollectionService
+	discogs.DatabaseService
+}
+
+// Item is an exported collection item.
+type Item struct {
+	discogs.CollectionItem
+	// Folder is the name of the folder holding the item.
+	Folder string `json:"folder"`
+	// Fields holds the custom field values by field name.
+	Fields map[string]string `json:"fields,omitempty"`
+	// Release is the full release, set if the exporter resolves releases.
+	Release *discogs.Release `json:"release,omitempty"`
+}
+
+// Exporter walks a user's collection.
+type Exporter struct {
+	client  Client
+	options []discogs.Option
+
+	// Resolve fetches the full release of every item.
+	// This costs one request per item but fills in release dates and countries.
+	Resolve bool
+	// PerPage is the number of items requested per page (default is 100).
+	PerPage int
+	// Retries is the number of retries of a rate limited release (default is 3).
+	Retries int
+	// Backoff is the wait before retrying a rate limited release (default is one minute).
+	Backoff time.Duration
+}
+
+// New returns an exporter using client. options are passed to every
+// collection request, typically discogs.WithClient and discogs.WithCredentials.
+func New(client Client, options ...discogs.Option) *Exporter {
+	return &Exporter{
+		client:  client,
+		options: options,
+		PerPage: 100,
+		Retries: 3,
+		Backoff: time.Minute,
+	}
+}
+
+// Export writes the collection of username to w in the given format and
+// returns the number of items written.
+func (e *Exporter) Export(ctx context.Context, w io.Writer, username string, format Format) (int, error) {
+	fields, err := e.client.GetCollectionFields(ctx, username, e.options...)
+	if err != nil {
+		return 0, err
+	}
+	sort.SliceStable(fields.Fields, func(i, j int) bool {
+		return fields.Fields[i].Position < fields.Fields[j].Position
+	})
+
+	var out writer
+	switch format {
+	case CSV:
+		out = &csvWriter{w: csv.NewWriter(w), fields: fields.Fields}
+	case JSONLines:
+		out = &jsonWriter{enc: json.NewEncoder(w)}
+	case Flat:
+		out = &flatWriter{w: csv.NewWriter(w), fields: fields.Fields}
+	default:
+		return 0, fmt.Errorf("export: unknown format %d", format)
+	}
+
+	if err := out.header(); err != nil {
+		return 0, err
+	}
+
+	var n int
+	err = e.walk(ctx, username, fields.Fields, func(item *Item) error {
+		if err := out.write(item); err != nil {
+			return err
+		}
+		n++
+		return nil
+	})
+	if ferr := out.flush(); err == nil {
+		err = ferr
+	}
+	return n, err
+}
+
+// Walk calls fn for every item of the collection of username, folder by folder.
+func (e *Exporter) Walk(ctx context.Context, username string, fn func(*Item) error) error {
+	fields, err := e.client.GetCollectionFields(ctx, username, e.options...)
+	if err != nil {
+		return err
+	}
+	return e.walk(ctx, username, fields.Fields, fn)
+}
+
+func (e *Exporter) walk(ctx context.Context, username string, fields []discogs.Field, fn func(*Item) error) error {
+	folders, err := e.client.GetFolders(ctx, username, e.options...)
+	if err != nil {
+		return err
+	}
+
+	names := make(map[int]string, len(fields))
+	for _, f := range fields {
+		names[f.ID] = f.Name
+	}
+
+	for _, folder := range folders.Folders {
+		// Folder 0 lists the items of all other folders.
+		if folder.ID == 0 && len(folders.Folders) > 1 {
+			continue
+		}
+
+		for page := 1; ; page++ {
+			items, err := e.client.GetCollectionItems(ctx, username, folder.ID, &discogs.Pagination{
+				Page:    page,
+				PerPage: e.PerPage,
+			}, e.options...)
+			if err != nil {
+				return err
+			}
+
+			for _, ci := range items.Releases {
+				item := &Item{
+					CollectionItem: ci,
+					Folder:         folder.Name,
+				}
+				for _, note := range ci.Notes {
+					if item.Fields == nil {
+						item.Fields = make(map[string]string)
+					}
+					if name, ok := names[note.FieldID]; ok {
+						item.Fields[name] = note.Value
+					} else {
+						item.Fields[strconv.Itoa(note.FieldID)] = note.Value
+					}
+				}
+				if e.Resolve {
+					if item.Release, err = e.release(ctx, ci.ID); err != nil {
+						return fmt.Errorf("export: failed to resolve release %d: %w", ci.ID, err)
+					}
+				}
+
+				if err := fn(item); err != nil {
+					return err
+				}
+			}
+
+			if page >= items.Pagination.Pages {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// release fetches a release, retrying after the backoff while it is rate
+// limited. Release takes no context, so ctx is checked before every attempt.
+func (e *Exporter) release(ctx context.Context, releaseID int) (*discogs.Release, error) {
+	for attempt := 0; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		r, err := e.client.Release(releaseID)
+		if !errors.Is(err, discogs.ErrTooManyRequests) || attempt >= e.Retries {
+			return r, err
+		}
+		if err := wait(ctx, e.Backoff); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// wait waits for d or until ctx is done.
+func wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+type writer interface {
+	header() error
+	write(item *Item) error
+	flush() error
+}
+
+type jsonWriter struct {
+	enc *json.Encoder
+}
+
+func (w *jsonWriter) header() error {
+	return nil
+}
+
+func (w *jsonWriter) write(item *Item) error {
+	return w.enc.Encode(item)
+}
+
+func (w *jsonWriter) flush() error {
+	return nil
+}
+
+// csvWriter writes the columns of the discogs.com collection export,
+// followed by one "Collection <name>" column per custom field.
+type csvWriter struct {
+	w      *csv.Writer
+	fields []discogs.Field
+}
+
+func (w *csvWriter) header() error {
+	header := []string{"Catalog#", "Artist", "Title", "Label", "Format", "Rating", "Released", "release_id", "CollectionFolder", "Date Added"}
+	for _, f := range w.fields {
+		header = append(header, "Collection "+f.Name)
+	}
+	return w.w.Write(header)
+}
+
+func (w *csvWriter) write(item *Item) error {
+	info := item.BasicInformation
+
+	var catnos, labels, formats []string
+	for _, l := range info.Labels {
+		catnos = append(catnos, l.Catno)
+		labels = append(labels, l.Name)
+	}
+	for _, f := range info.Formats {
+		name := f.Name
+		if f.Qty != "" && f.Qty != "1" {
+			name = f.Qty + "x" + name
+		}
+		formats = append(formats, strings.Join(append([]string{name}, f.Descriptions...), ", "))
+	}
+
+	released := ""
+	if info.Year != 0 {
+		released = strconv.Itoa(info.Year)
+	}
+	if item.Release != nil && item.Release.Released != "" {
+		released = item.Release.Released
+	}
+
+	rating := ""
+	if item.Rating != 0 {
+		rating = strconv.Itoa(item.Rating)
+	}
+
+	record := []string{
+		strings.Join(catnos, ", "),
+		discogs.DisplayArtists(info.Artists),
+		info.Title,
+		strings.Join(labels, ", "),
+		strings.Join(formats, ", "),
+		rating,
+		released,
+		strconv.Itoa(item.ID),
+		item.Folder,
+		dateAdded(item.DateAdded),
+	}
+	for _, f := range w.fields {
+		record = append(record, item.Fields[f.Name])
+	}
+	return w.w.Write(record)
+}
+
+func (w *csvWriter) flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// flatWriter writes every field of an item in its own column.
+type flatWriter struct {
+	w      *csv.Writer
+	fields []discogs.Field
+}
+
+const flatSeparator = "; "
+
+func (w *flatWriter) header() error {
+	header := []string{
+		"release_id", "instance_id", "folder_id", "folder", "date_added", "rating",
+		"artists", "title", "labels", "catalog_numbers", "formats", "format_quantity", "format_descriptions",
+		"year", "released", "country", "genres", "styles", "master_id",
+	}
+	for _, f := range w.fields {
+		header = append(header, f.Name)
+	}
+	return w.w.Write(header)
+}
+
+func (w *flatWriter) write(item *Item) error {
+	info := item.BasicInformation
+
+	var catnos, labels, formats, descriptions []string
+	var qty int
+	for _, l := range info.Labels {
+		catnos = append(catnos, l.Catno)
+		labels = append(labels, l.Name)
+	}
+	for _, f := range info.Formats {
+		formats = append(formats, f.Name)
+		descriptions = append(descriptions, f.Descriptions...)
+		if n, err := strconv.Atoi(f.Qty); err == nil {
+			qty += n
+		}
+	}
+
+	var released, country string
+	if item.Release != nil {
+		released = item.Release.Released
+		country = item.Release.Country
+	}
+
+	record := []string{
+		strconv.Itoa(item.ID),
+		strconv.Itoa(item.InstanceID),
+		strconv.Itoa(item.FolderID),
+		item.Folder,
+		item.DateAdded,
+		strconv.Itoa(item.Rating),
+		discogs.DisplayArtists(info.Artists),
+		info.Title,
+		strings.Join(labels, flatSeparator),
+		strings.Join(catnos, flatSeparator),
+		strings.Join(formats, flatSeparator),
+		strconv.Itoa(qty),
+		strings.Join(descriptions, flatSeparator),
+		strconv.Itoa(info.Year),
+		released,
+		country,
+		strings.Join(info.Genres, flatSeparator),
+		strings.Join(info.Styles, flatSeparator),
+		strconv.Itoa(info.MasterID),
+	}
+	for _, f := range w.fields {
+		record = append(record, item.Fields[f.Name])
+	}
+	return w.w.Write(record)
+}
+
+func (w *flatWriter) flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// dateAdded formats an API timestamp the way discogs.com exports it.
+func dateAdded(s string) string {
+	t, err := discogs.ParseTimestamp(s)
+	if err != nil {
+		return s
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
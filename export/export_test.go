@@ -0,0 +1,194 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gomodule/oauth1/oauth"
+	"github.com/ninnemana/go-discogs"
+	"github.com/ninnemana/go-discogs/discogstest"
+)
+
+func newExporter(t *testing.T) *Exporter {
+	t.Helper()
+	s := discogstest.NewTestServer(t)
+	s.AddFolder("someuser", discogs.Folder{ID: 0, Name: "All", Count: 3})
+	s.AddFolder("someuser", discogs.Folder{ID: 1, Name: "Uncategorized", Count: 1})
+	s.AddFolder("someuser", discogs.Folder{ID: 2, Name: "Ska", Count: 2})
+	s.AddCollectionField("someuser", discogs.Field{ID: 2, Name: "Sleeve Condition", Type: "dropdown", Position: 2})
+	s.AddCollectionField("someuser", discogs.Field{ID: 1, Name: "Media Condition", Type: "dropdown", Position: 1})
+	s.AddRelease(discogs.Release{ID: 8138518, Title: "Elephant Riddim", Released: "2016-02-18", Country: "Russia"})
+
+	info := discogs.BasicInformation{
+		ID:      8138518,
+		Title:   "Elephant Riddim",
+		Year:    2016,
+		Artists: []discogs.ArtistSource{{Name: "St. Petersburg Ska-Jazz Review (2)", Join: "Feat."}, {Name: "Sergey Ponomarev", Anv: "S. Ponomarev"}},
+		Labels:  []discogs.LabelSource{{Name: "Magnetic Loft Records", Catno: "MLR-007"}},
+		Formats: []discogs.Format{{Name: "Vinyl", Qty: "1", Descriptions: []string{"LP", "Album"}}},
+	}
+	s.AddCollectionItem("someuser", discogs.CollectionItem{
+		ID:               8138518,
+		InstanceID:       1,
+		FolderID:         2,
+		Rating:           5,
+		DateAdded:        "2020-01-02T03:04:05-08:00",
+		BasicInformation: info,
+		Notes:            []discogs.Note{{FieldID: 1, Value: "Mint (M)"}, {FieldID: 2, Value: "Near Mint (NM or M-)"}},
+	})
+	s.AddCollectionItem("someuser", discogs.CollectionItem{ID: 8138518, InstanceID: 2, FolderID: 2, BasicInformation: info})
+	s.AddCollectionItem("someuser", discogs.CollectionItem{ID: 8138518, InstanceID: 3, FolderID: 1, BasicInformation: info})
+
+	client := s.NewClient(t, "")
+
+	e := New(client,
+		discogs.WithClient(&oauth.Client{}),
+		discogs.WithCredentials(&oauth.Credentials{Token: "token", Secret: "secret"}),
+	)
+	e.PerPage = 1
+	return e
+}
+
+func TestExportCSV(t *testing.T) {
+	e := newExporter(t)
+
+	var buf bytes.Buffer
+	n, err := e.Export(context.Background(), &buf, "someuser", CSV)
+	if err != nil {
+		t.Fatalf("failed to export: %s", err)
+	}
+	if n != 3 {
+		t.Errorf("items got=%d; want=3", n)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read csv: %s", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("records got=%d; want=4", len(records))
+	}
+
+	want := []string{"Catalog#", "Artist", "Title", "Label", "Format", "Rating", "Released", "release_id", "CollectionFolder", "Date Added", "Collection Media Condition", "Collection Sleeve Condition"}
+	if strings.Join(records[0], "|") != strings.Join(want, "|") {
+		t.Errorf("header got=%v; want=%v", records[0], want)
+	}
+
+	want = []string{"MLR-007", "St. Petersburg Ska-Jazz Review Feat. S. Ponomarev*", "Elephant Riddim", "Magnetic Loft Records", "Vinyl, LP, Album", "", "2016", "8138518", "Uncategorized", "", "", ""}
+	if strings.Join(records[1], "|") != strings.Join(want, "|") {
+		t.Errorf("record got=%v; want=%v", records[1], want)
+	}
+	if got := records[2]; got[5] != "5" || got[8] != "Ska" || got[9] != "2020-01-02 03:04:05" || got[10] != "Mint (M)" {
+		t.Errorf("record got=%v", got)
+	}
+}
+
+func TestExportJSONLinesResolved(t *testing.T) {
+	e := newExporter(t)
+	e.Resolve = true
+
+	var buf bytes.Buffer
+	if _, err := e.Export(context.Background(), &buf, "someuser", JSONLines); err != nil {
+		t.Fatalf("failed to export: %s", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("lines got=%d; want=3", len(lines))
+	}
+
+	var item Item
+	if err := json.Unmarshal([]byte(lines[1]), &item); err != nil {
+		t.Fatalf("failed to unmarshal item: %s", err)
+	}
+	if item.InstanceID != 1 || item.Folder != "Ska" || item.Fields["Sleeve Condition"] != "Near Mint (NM or M-)" {
+		t.Errorf("item got=%+v", item)
+	}
+	if item.Release == nil || item.Release.Country != "Russia" {
+		t.Errorf("release got=%+v", item.Release)
+	}
+}
+
+// limitedClient rate limits the first releases fetched.
+type limitedClient struct {
+	Client
+	limited int
+	calls   int
+}
+
+func (c *limitedClient) Release(id int) (*discogs.Release, error) {
+	c.calls++
+	if c.calls <= c.limited {
+		return nil, discogs.ErrTooManyRequests
+	}
+	return c.Client.Release(id)
+}
+
+func TestExportResolveRateLimit(t *testing.T) {
+	e := newExporter(t)
+	e.Resolve = true
+	e.Backoff = 0
+	lc := &limitedClient{Client: e.client, limited: 2}
+	e.client = lc
+
+	var buf bytes.Buffer
+	if n, err := e.Export(context.Background(), &buf, "someuser", JSONLines); err != nil || n != 3 {
+		t.Fatalf("export got=%d, %v; want=3", n, err)
+	}
+	if lc.calls != 5 {
+		t.Errorf("release calls got=%d; want=5", lc.calls)
+	}
+
+	lc.calls, lc.limited = 0, 10
+	if _, err := e.Export(context.Background(), &buf, "someuser", JSONLines); !errors.Is(err, discogs.ErrTooManyRequests) {
+		t.Errorf("err got=%v; want=%v", err, discogs.ErrTooManyRequests)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	lc.calls, lc.limited = 0, 0
+	e.client = &cancelingClient{Client: lc, cancel: cancel}
+	if _, err := e.Export(ctx, &buf, "someuser", JSONLines); !errors.Is(err, context.Canceled) {
+		t.Errorf("err got=%v; want=%v", err, context.Canceled)
+	}
+	if lc.calls != 1 {
+		t.Errorf("release calls after cancel got=%d; want=1", lc.calls)
+	}
+}
+
+// cancelingClient cancels the export once a release is fetched.
+type cancelingClient struct {
+	Client
+	cancel func()
+}
+
+func (c *cancelingClient) Release(id int) (*discogs.Release, error) {
+	defer c.cancel()
+	return c.Client.Release(id)
+}
+
+func TestExportFlat(t *testing.T) {
+	e := newExporter(t)
+
+	var buf bytes.Buffer
+	if _, err := e.Export(context.Background(), &buf, "someuser", Flat); err != nil {
+		t.Fatalf("failed to export: %s", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read csv: %s", err)
+	}
+	header := records[0]
+	if header[len(header)-2] != "Media Condition" {
+		t.Errorf("header got=%v", header)
+	}
+	if got := records[2]; got[12] != "LP; Album" || got[len(got)-2] != "Mint (M)" {
+		t.Errorf("record got=%v", got)
+	}
+}
@@ -0,0 +1,162 @@
+// Package facet counts the genres, styles, formats, countries and decades of
+// search results, which the search API does not return itself.
+//
+// The counts are taken over the first results of a search, up to a limit:
+//
+//	c := facet.New(client)
+//	c.Limit = 1000
+//	f, err := c.Count(ctx, discogs.SearchRequest{Q: "dub", Type: discogs.SearchTypeRelease})
+//	if err != nil {
+//		return err
+//	}
+//	for _, g := range f.Genre {
+//		fmt.Println(g.Value, g.Count)
+//	}
+package facet
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/ninnemana/go-discogs"
+)
+
+// Count is the number of results having a value.
+type Count struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Facets holds the counts of a search, most frequent value first.
+type Facets struct {
+	// Items is the total number of results of the search reported by the API.
+	Items int `json:"items"`
+	// Counted is the number of results counted, after deduplication.
+	Counted int     `json:"counted"`
+	Genre   []Count `json:"genre"`
+	Style   []Count `json:"style"`
+	Format  []Count `json:"format"`
+	Country []Count `json:"country"`
+	// Decade counts years by decade, like "1990s".
+	Decade []Count `json:"decade"`
+}
+
+// Counter pages through searches and counts their results.
+type Counter struct {
+	client discogs.SearchService
+
+	// Limit is the number of results fetched at most (default is 500).
+	Limit int
+	// PerPage is the number of results requested per page (default is 100).
+	PerPage int
+	// Dedupe counts the results of a master release once (default is true).
+	// Results without a master release are always counted.
+	Dedupe bool
+}
+
+// New returns a counter using client.
+func New(client discogs.SearchService) *Counter {
+	return &Counter{
+		client:  client,
+		Limit:   500,
+		PerPage: 100,
+		Dedupe:  true,
+	}
+}
+
+// Count searches req from its first page and counts up to Limit results.
+func (c *Counter) Count(ctx context.Context, req discogs.SearchRequest) (*Facets, error) {
+	var (
+		f       = &Facets{}
+		fetched int
+		masters = make(map[int]bool)
+		counts  = make(map[string]map[string]int)
+	)
+	add := func(facet string, values []string) {
+		if counts[facet] == nil {
+			counts[facet] = make(map[string]int)
+		}
+		seen := make(map[string]bool, len(values))
+		for _, v := range values {
+			if v == "" || seen[v] {
+				continue
+			}
+			seen[v] = true
+			counts[facet][v]++
+		}
+	}
+
+	req.PerPage = c.PerPage
+	if c.Limit < req.PerPage {
+		req.PerPage = c.Limit
+	}
+
+	for page := 1; fetched < c.Limit; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		req.Page = page
+		search, err := c.client.Search(req)
+		if err != nil {
+			return nil, err
+		}
+		f.Items = search.Pagination.Items
+
+		for _, r := range search.Results {
+			if fetched == c.Limit {
+				break
+			}
+			fetched++
+
+			if c.Dedupe && r.MasterID != 0 {
+				if masters[r.MasterID] {
+					continue
+				}
+				masters[r.MasterID] = true
+			}
+
+			f.Counted++
+			add("genre", r.Genre)
+			add("style", r.Style)
+			add("format", r.Format)
+			add("country", []string{r.Country})
+			add("decade", []string{decade(r.Year)})
+		}
+
+		if len(search.Results) == 0 || page >= search.Pagination.Pages {
+			break
+		}
+	}
+
+	f.Genre = sorted(counts["genre"])
+	f.Style = sorted(counts["style"])
+	f.Format = sorted(counts["format"])
+	f.Country = sorted(counts["country"])
+	f.Decade = sorted(counts["decade"])
+	return f, nil
+}
+
+// decade returns the decade of a year, like "1990s", or "" if year is not a number.
+func decade(year string) string {
+	y, err := strconv.Atoi(year)
+	if err != nil || y <= 0 {
+		return ""
+	}
+	return strconv.Itoa(y-y%10) + "s"
+}
+
+func sorted(m map[string]int) []Count {
+	counts := make([]Count, 0, len(m))
+	for v, n := range m {
+		counts = append(counts, Count{Value: v, Count: n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Value < counts[j].Value
+	})
+	return counts
+}
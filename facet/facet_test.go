@@ -0,0 +1,76 @@
+package facet
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/ninnemana/go-discogs"
+	"github.com/ninnemana/go-discogs/discogstest"
+)
+
+func newCounter(t *testing.T) *Counter {
+	t.Helper()
+	s := discogstest.NewTestServer(t)
+	for _, r := range []discogs.Release{
+		{ID: 1, MasterID: 10, Genres: []string{"Rock"}, Styles: []string{"Grunge"}, Country: "US", Year: 1991,
+			Formats: []discogs.Format{{Name: "Vinyl", Descriptions: []string{"LP"}}}},
+		{ID: 2, MasterID: 10, Genres: []string{"Rock"}, Styles: []string{"Grunge"}, Country: "UK", Year: 1992,
+			Formats: []discogs.Format{{Name: "CD"}}},
+		{ID: 3, Genres: []string{"Electronic"}, Styles: []string{"Dub"}, Country: "UK", Year: 2005,
+			Formats: []discogs.Format{{Name: "Vinyl"}, {Name: "Vinyl"}}},
+		{ID: 4, MasterID: 20, Genres: []string{"Rock"}, Styles: []string{"Punk"}, Country: "US", Year: 1977,
+			Formats: []discogs.Format{{Name: "Vinyl"}}},
+		{ID: 5, Genres: []string{"Electronic"}, Styles: []string{"Techno"}, Country: "Germany", Year: 1999,
+			Formats: []discogs.Format{{Name: "CD"}}},
+	} {
+		s.AddRelease(r)
+	}
+
+	c := New(s.NewClient(t, "token"))
+	c.PerPage = 2
+	return c
+}
+
+func TestCount(t *testing.T) {
+	c := newCounter(t)
+
+	f, err := c.Count(context.Background(), discogs.SearchRequest{Type: discogs.SearchTypeRelease})
+	if err != nil {
+		t.Fatalf("failed to count: %s", err)
+	}
+	if f.Items != 5 || f.Counted != 4 {
+		t.Errorf("items got=%d, counted got=%d; want=5, 4", f.Items, f.Counted)
+	}
+
+	tests := map[string]struct {
+		got, want []Count
+	}{
+		"genre":   {f.Genre, []Count{{"Electronic", 2}, {"Rock", 2}}},
+		"format":  {f.Format, []Count{{"Vinyl", 3}, {"CD", 1}, {"LP", 1}}},
+		"country": {f.Country, []Count{{"US", 2}, {"Germany", 1}, {"UK", 1}}},
+		"decade":  {f.Decade, []Count{{"1990s", 2}, {"1970s", 1}, {"2000s", 1}}},
+	}
+	for name, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s got=%v; want=%v", name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestCountLimit(t *testing.T) {
+	c := newCounter(t)
+	c.Limit = 3
+	c.Dedupe = false
+
+	f, err := c.Count(context.Background(), discogs.SearchRequest{Type: discogs.SearchTypeRelease})
+	if err != nil {
+		t.Fatalf("failed to count: %s", err)
+	}
+	if f.Counted != 3 {
+		t.Errorf("counted got=%d; want=3", f.Counted)
+	}
+	if want := []Count{{"Rock", 2}, {"Electronic", 1}}; !reflect.DeepEqual(f.Genre, want) {
+		t.Errorf("genre got=%v; want=%v", f.Genre, want)
+	}
+}
@@ -0,0 +1,174 @@
+package discogs
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Format descriptions by category, as used by Discogs.
+var (
+	formatSizes = map[string]bool{
+		`3"`: true, `3.5"`: true, `5"`: true, `5.25"`: true, `6"`: true, `7"`: true,
+		`8"`: true, `9"`: true, `10"`: true, `11"`: true, `12"`: true, `16"`: true,
+	}
+	formatChannels = map[string]bool{
+		"Mono": true, "Stereo": true, "Quadraphonic": true, "Multichannel": true, "Ambisonic": true,
+	}
+	formatTypes = map[string]bool{
+		"LP": true, "EP": true, "Single": true, "Maxi-Single": true, "Album": true,
+		"Mini-Album": true, "Compilation": true, "Sampler": true, "Mixtape": true,
+	}
+	// formatContainers hold other formats rather than being a medium themselves.
+	formatContainers = map[string]bool{
+		"Box Set": true, "All Media": true,
+	}
+)
+
+// FormatInfo is a decoded Format.
+type FormatInfo struct {
+	Medium  string   `json:"medium"`            // the format name, like "Vinyl" or "CD"
+	Qty     int      `json:"qty"`               // number of media
+	Size    string   `json:"size,omitempty"`    // like `12"`
+	Speed   string   `json:"speed,omitempty"`   // like "33 ⅓ RPM"
+	Channel string   `json:"channel,omitempty"` // Mono, Stereo, Quadraphonic, Multichannel or Ambisonic
+	Types   []string `json:"types,omitempty"`   // LP, EP, Single, Album, Compilation and the like
+	Text    string   `json:"text,omitempty"`    // free text, like "Red Marbled"
+
+	Reissue      bool `json:"reissue,omitempty"`
+	Repress      bool `json:"repress,omitempty"`
+	Remastered   bool `json:"remastered,omitempty"`
+	Promo        bool `json:"promo,omitempty"`
+	TestPressing bool `json:"test_pressing,omitempty"`
+	Limited      bool `json:"limited,omitempty"`
+	Numbered     bool `json:"numbered,omitempty"`
+	Unofficial   bool `json:"unofficial,omitempty"`
+
+	// Other holds the descriptions not decoded into a field, like "Picture Disc".
+	Other []string `json:"other,omitempty"`
+}
+
+// Container reports whether the format holds other formats, like a box set.
+func (f FormatInfo) Container() bool {
+	return formatContainers[f.Medium]
+}
+
+// DecodeFormat classifies the descriptions of a format.
+// A missing or invalid quantity counts as one medium.
+func DecodeFormat(f Format) FormatInfo {
+	info := FormatInfo{Medium: f.Name, Text: f.Text, Qty: 1}
+	if qty, err := strconv.Atoi(strings.TrimSpace(f.Qty)); err == nil && qty >= 0 {
+		info.Qty = qty
+	}
+
+	for _, d := range f.Descriptions {
+		d = strings.TrimSpace(d)
+		switch {
+		case formatSizes[d]:
+			info.Size = d
+		case strings.HasSuffix(d, "RPM"):
+			info.Speed = normalizeSpeed(d)
+		case formatChannels[d]:
+			info.Channel = d
+		case formatTypes[d]:
+			info.Types = append(info.Types, d)
+		case d == "Reissue":
+			info.Reissue = true
+		case d == "Repress":
+			info.Repress = true
+		case d == "Remastered":
+			info.Remastered = true
+		case d == "Promo":
+			info.Promo = true
+		case d == "Test Pressing":
+			info.TestPressing = true
+		case d == "Limited Edition":
+			info.Limited = true
+		case d == "Numbered":
+			info.Numbered = true
+		case d == "Unofficial Release", d == "Partially Unofficial":
+			info.Unofficial = true
+		case d != "":
+			info.Other = append(info.Other, d)
+		}
+	}
+	return info
+}
+
+// normalizeSpeed writes fractions of speeds like "33 1/3 RPM" the way Discogs does.
+func normalizeSpeed(s string) string {
+	s = strings.Replace(s, "1/3", "⅓", 1)
+	s = strings.Replace(s, "2/3", "⅔", 1)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// FormatSummary is the decoded formats of a release.
+type FormatSummary struct {
+	Formats []FormatInfo `json:"formats"`
+	// Discs is the number of media, not counting containers like box sets.
+	Discs int `json:"discs"`
+}
+
+// DecodeFormats classifies the formats of a release.
+func DecodeFormats(formats []Format) FormatSummary {
+	var s FormatSummary
+	for _, f := range formats {
+		info := DecodeFormat(f)
+		if !info.Container() {
+			s.Discs += info.Qty
+		}
+		s.Formats = append(s.Formats, info)
+	}
+	return s
+}
+
+func (s FormatSummary) any(flag func(FormatInfo) bool) bool {
+	for _, f := range s.Formats {
+		if flag(f) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reissue reports whether any format is a reissue or repress.
+func (s FormatSummary) Reissue() bool {
+	return s.any(func(f FormatInfo) bool { return f.Reissue || f.Repress })
+}
+
+// Remastered reports whether any format is remastered.
+func (s FormatSummary) Remastered() bool {
+	return s.any(func(f FormatInfo) bool { return f.Remastered })
+}
+
+// Promo reports whether any format is a promo.
+func (s FormatSummary) Promo() bool {
+	return s.any(func(f FormatInfo) bool { return f.Promo })
+}
+
+// TestPressing reports whether any format is a test pressing.
+func (s FormatSummary) TestPressing() bool {
+	return s.any(func(f FormatInfo) bool { return f.TestPressing })
+}
+
+// Unofficial reports whether any format is an unofficial release.
+func (s FormatSummary) Unofficial() bool {
+	return s.any(func(f FormatInfo) bool { return f.Unofficial })
+}
+
+// Original reports whether the release is an original pressing:
+// not a reissue, repress, remaster, test pressing or unofficial release.
+func (s FormatSummary) Original() bool {
+	return !s.any(func(f FormatInfo) bool {
+		return f.Reissue || f.Repress || f.Remastered || f.TestPressing || f.Unofficial
+	})
+}
+
+// Has reports whether any format has the medium, like "Vinyl".
+func (s FormatSummary) Has(medium string) bool {
+	return s.any(func(f FormatInfo) bool { return strings.EqualFold(f.Medium, medium) })
+}
+
+// DecodedFormats classifies the formats of the release.
+func (r *Release) DecodedFormats() FormatSummary {
+	return DecodeFormats(r.Formats)
+}
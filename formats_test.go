@@ -0,0 +1,82 @@
+package discogs
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeFormat(t *testing.T) {
+	got := DecodeFormat(Format{
+		Name:         "Vinyl",
+		Qty:          "2",
+		Text:         "Red Marbled",
+		Descriptions: []string{"LP", "Album", `12"`, "33 1/3 RPM", "Stereo", "Reissue", "Limited Edition", "Picture Disc"},
+	})
+	want := FormatInfo{
+		Medium:  "Vinyl",
+		Qty:     2,
+		Size:    `12"`,
+		Speed:   "33 ⅓ RPM",
+		Channel: "Stereo",
+		Types:   []string{"LP", "Album"},
+		Text:    "Red Marbled",
+		Reissue: true,
+		Limited: true,
+		Other:   []string{"Picture Disc"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("format got=%+v; want=%+v", got, want)
+	}
+
+	if qty := DecodeFormat(Format{Name: "CD"}).Qty; qty != 1 {
+		t.Errorf("missing qty got=%d; want=1", qty)
+	}
+}
+
+func TestDecodeFormats(t *testing.T) {
+	tests := map[string]struct {
+		formats  []Format
+		discs    int
+		original bool
+	}{
+		"original": {
+			[]Format{{Name: "Vinyl", Qty: "1", Descriptions: []string{"LP", "Album"}}},
+			1, true,
+		},
+		"box set": {
+			[]Format{
+				{Name: "Box Set", Qty: "1", Descriptions: []string{"Limited Edition"}},
+				{Name: "Vinyl", Qty: "3", Descriptions: []string{"LP", "Remastered"}},
+				{Name: "CD", Qty: "1", Descriptions: []string{"Album"}},
+			},
+			4, false,
+		},
+		"test pressing": {
+			[]Format{{Name: "Vinyl", Qty: "1", Descriptions: []string{"Test Pressing"}}},
+			1, false,
+		},
+		"repress": {
+			[]Format{{Name: "Vinyl", Qty: "1", Descriptions: []string{`7"`, "45 RPM", "Single", "Repress"}}},
+			1, false,
+		},
+	}
+	for name, tt := range tests {
+		got := DecodeFormats(tt.formats)
+		if got.Discs != tt.discs || got.Original() != tt.original {
+			t.Errorf("%s: got discs=%d original=%t; want discs=%d original=%t",
+				name, got.Discs, got.Original(), tt.discs, tt.original)
+		}
+	}
+}
+
+func TestReleaseDecodedFormats(t *testing.T) {
+	var r Release
+	if err := json.Unmarshal([]byte(releaseJson), &r); err != nil {
+		t.Fatalf("failed to decode release: %s", err)
+	}
+	f := r.DecodedFormats()
+	if !f.Has("vinyl") || f.Discs != 1 || !f.Original() || f.Formats[0].Channel != "Stereo" {
+		t.Errorf("formats got=%+v", f)
+	}
+}
@@ -5,5 +5,7 @@ go 1.14
 require (
 	github.com/gomodule/oauth1 v0.0.0-20181215000758-9a59ed3b0a84
 	github.com/google/go-cmp v0.4.1
+	github.com/mattn/go-sqlite3 v1.14.16
 	go.opencensus.io v0.22.5
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,256 @@
+// Package graph walks the members, groups and aliases of artists into a
+// graph, like the family tree of a band.
+//
+// A Walker fetches every artist once, caching it across walks, and stops at
+// a depth from the starting artist; artists seen twice close a cycle rather
+// than being walked again:
+//
+//	w := graph.New(client)
+//	w.Depth = 3
+//	g, err := w.Walk(ctx, 1743)
+//	if err != nil {
+//		return err
+//	}
+//	g.WriteDOT(os.Stdout)
+//
+// A LabelWalker builds the family tree of a label in the same way, from its
+// topmost parent label down through the sublabels:
+//
+//	lw := graph.NewLabelWalker(client)
+//	lw.Releases = true
+//	t, err := lw.Tree(ctx, 1)
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/ninnemana/go-discogs"
+)
+
+// Client is the part of the discogs client a Walker uses.
+type Client interface {
+	Artist(artistID int) (*discogs.Artist, error)
+}
+
+// Relation is the kind of an edge.
+type Relation string
+
+// Relations.
+const (
+	// RelationMember runs from a group to one of its members.
+	RelationMember Relation = "member"
+	// RelationAlias joins an artist to another name it performs under.
+	RelationAlias Relation = "alias"
+)
+
+// Node is an artist of the graph.
+type Node struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Depth is the number of edges walked from the starting artist.
+	Depth int `json:"depth"`
+	// Fetched reports whether the artist was fetched; artists at the maximum
+	// depth or not found are only known by the reference to them.
+	Fetched bool `json:"fetched"`
+}
+
+// Edge is a relation between two artists. Membership is stored once,
+// from the group to the member, whether it was found on the members of
+// the group or on the groups of the member.
+type Edge struct {
+	From     int      `json:"from"`
+	To       int      `json:"to"`
+	Relation Relation `json:"relation"`
+	// Active reports whether a member is still in the group; it is set for members only.
+	Active bool `json:"active,omitempty"`
+}
+
+// Graph is the result of a walk.
+type Graph struct {
+	Root  int    `json:"root"`
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// Node returns the node of an artist.
+func (g *Graph) Node(id int) (Node, bool) {
+	for _, n := range g.Nodes {
+		if n.ID == id {
+			return n, true
+		}
+	}
+	return Node{}, false
+}
+
+// Walker walks artist relations. Its fields configure the walks and must
+// not be changed during one.
+type Walker struct {
+	client Client
+
+	// Depth is the number of edges walked from the starting artist (default is 2).
+	Depth int
+	// Members, Groups and Aliases select the relations walked (default is all).
+	Members bool
+	Groups  bool
+	Aliases bool
+
+	mu    sync.Mutex
+	cache map[int]*discogs.Artist
+}
+
+// New returns a Walker fetching artists with client.
+func New(client Client) *Walker {
+	return &Walker{
+		client:  client,
+		Depth:   2,
+		Members: true,
+		Groups:  true,
+		Aliases: true,
+		cache:   make(map[int]*discogs.Artist),
+	}
+}
+
+// artist returns an artist from the cache or fetches it.
+func (w *Walker) artist(id int) (*discogs.Artist, error) {
+	w.mu.Lock()
+	a, ok := w.cache[id]
+	w.mu.Unlock()
+	if ok {
+		return a, nil
+	}
+
+	a, err := w.client.Artist(id)
+	if err != nil {
+		return nil, err
+	}
+	w.mu.Lock()
+	w.cache[id] = a
+	w.mu.Unlock()
+	return a, nil
+}
+
+// Walk walks the relations of an artist breadth first. Artists that are not
+// found stay in the graph unfetched; other errors stop the walk and return
+// the graph walked so far.
+func (w *Walker) Walk(ctx context.Context, artistID int) (*Graph, error) {
+	g := &Graph{Root: artistID}
+	nodes := map[int]int{}
+	edges := map[Edge]bool{}
+
+	addNode := func(id int, name string, depth int) {
+		if i, ok := nodes[id]; ok {
+			if g.Nodes[i].Name == "" {
+				g.Nodes[i].Name = name
+			}
+			return
+		}
+		nodes[id] = len(g.Nodes)
+		g.Nodes = append(g.Nodes, Node{ID: id, Name: name, Depth: depth})
+	}
+	addEdge := func(e Edge) {
+		if e.Relation == RelationAlias && e.From > e.To {
+			e.From, e.To = e.To, e.From
+		}
+		key := e
+		key.Active = false
+		if !edges[key] {
+			edges[key] = true
+			g.Edges = append(g.Edges, e)
+		}
+	}
+
+	addNode(artistID, "", 0)
+	queue := []int{artistID}
+	for len(queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return g, err
+		}
+
+		id := queue[0]
+		queue = queue[1:]
+		node := &g.Nodes[nodes[id]]
+		if node.Depth >= w.Depth {
+			continue
+		}
+
+		a, err := w.artist(id)
+		if errors.Is(err, discogs.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return g, fmt.Errorf("artist %d: %w", id, err)
+		}
+		node.Name = a.Name
+		node.Fetched = true
+		depth := node.Depth + 1
+
+		visit := func(id int, name string) {
+			if _, ok := nodes[id]; !ok {
+				queue = append(queue, id)
+			}
+			addNode(id, name, depth)
+		}
+		if w.Members {
+			for _, m := range a.Members {
+				visit(m.ID, m.Name)
+				addEdge(Edge{From: a.ID, To: m.ID, Relation: RelationMember, Active: m.Active})
+			}
+		}
+		if w.Groups {
+			for _, gr := range a.Groups {
+				visit(gr.ID, gr.Name)
+				addEdge(Edge{From: gr.ID, To: a.ID, Relation: RelationMember, Active: gr.Active})
+			}
+		}
+		if w.Aliases {
+			for _, al := range a.Aliases {
+				visit(al.ID, al.Name)
+				addEdge(Edge{From: a.ID, To: al.ID, Relation: RelationAlias})
+			}
+		}
+	}
+	return g, nil
+}
+
+// WriteDOT writes the graph in the Graphviz DOT language. Aliases are
+// dashed and undirected; former members are grey.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	ew := &errWriter{w: w}
+	ew.printf("digraph artists {\n")
+	for _, n := range g.Nodes {
+		attrs := "label=" + strconv.Quote(n.Name)
+		if n.ID == g.Root {
+			attrs += ", style=bold"
+		}
+		ew.printf("\t%d [%s];\n", n.ID, attrs)
+	}
+	for _, e := range g.Edges {
+		attrs := "label=" + strconv.Quote(string(e.Relation))
+		switch {
+		case e.Relation == RelationAlias:
+			attrs += ", style=dashed, dir=none"
+		case !e.Active:
+			attrs += ", color=grey"
+		}
+		ew.printf("\t%d -> %d [%s];\n", e.From, e.To, attrs)
+	}
+	ew.printf("}\n")
+	return ew.err
+}
+
+// errWriter keeps the first write error.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...interface{}) {
+	if ew.err == nil {
+		_, ew.err = fmt.Fprintf(ew.w, format, args...)
+	}
+}
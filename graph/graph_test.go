@@ -0,0 +1,144 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ninnemana/go-discogs"
+	"github.com/ninnemana/go-discogs/discogstest"
+)
+
+// countingClient counts the artists fetched.
+type countingClient struct {
+	Client
+	fetched map[int]int
+}
+
+func (c *countingClient) Artist(id int) (*discogs.Artist, error) {
+	c.fetched[id]++
+	return c.Client.Artist(id)
+}
+
+func newWalker(t *testing.T) (*Walker, *countingClient) {
+	t.Helper()
+	s := discogstest.NewTestServer(t)
+	s.AddArtist(discogs.Artist{ID: 1, Name: "The Persuader",
+		Groups:  []discogs.Group{{ID: 1743, Name: "Exos", Active: true}},
+		Aliases: []discogs.Alias{{ID: 239, Name: "Jesper Dahlbäck"}},
+	})
+	s.AddArtist(discogs.Artist{ID: 239, Name: "Jesper Dahlbäck",
+		Aliases: []discogs.Alias{{ID: 1, Name: "The Persuader"}},
+	})
+	s.AddArtist(discogs.Artist{ID: 1743, Name: "Exos",
+		Members: []discogs.Member{{ID: 1, Name: "The Persuader", Active: true}, {ID: 2, Name: "Former Member"}},
+	})
+	s.AddArtist(discogs.Artist{ID: 2, Name: "Former Member",
+		Aliases: []discogs.Alias{{ID: 3, Name: "Deep Alias"}},
+	})
+
+	cc := &countingClient{Client: s.NewClient(t, ""), fetched: map[int]int{}}
+	return New(cc), cc
+}
+
+func TestWalk(t *testing.T) {
+	w, cc := newWalker(t)
+
+	g, err := w.Walk(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("failed to walk: %s", err)
+	}
+
+	if len(g.Nodes) != 4 {
+		t.Errorf("nodes got=%+v", g.Nodes)
+	}
+	if n, _ := g.Node(2); n.Depth != 2 || n.Fetched || n.Name != "Former Member" {
+		t.Errorf("depth limited node got=%+v", n)
+	}
+	if _, ok := g.Node(3); ok {
+		t.Errorf("walked past the depth")
+	}
+
+	want := []Edge{
+		{From: 1743, To: 1, Relation: RelationMember, Active: true},
+		{From: 1, To: 239, Relation: RelationAlias},
+		{From: 1743, To: 2, Relation: RelationMember},
+	}
+	if len(g.Edges) != len(want) {
+		t.Fatalf("edges got=%+v; want=%+v", g.Edges, want)
+	}
+	for i := range want {
+		if g.Edges[i] != want[i] {
+			t.Errorf("edge %d got=%+v; want=%+v", i, g.Edges[i], want[i])
+		}
+	}
+
+	if _, err := w.Walk(context.Background(), 1); err != nil {
+		t.Fatalf("failed to walk again: %s", err)
+	}
+	for id, n := range cc.fetched {
+		if n != 1 {
+			t.Errorf("artist %d fetched %d times", id, n)
+		}
+	}
+}
+
+func TestWalkNotFound(t *testing.T) {
+	w, _ := newWalker(t)
+	w.Depth = 5
+
+	g, err := w.Walk(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("failed to walk: %s", err)
+	}
+	if n, ok := g.Node(3); !ok || n.Fetched || n.Name != "Deep Alias" {
+		t.Errorf("missing artist got=%+v", n)
+	}
+}
+
+func TestWalkRelations(t *testing.T) {
+	w, _ := newWalker(t)
+	w.Groups = false
+
+	g, err := w.Walk(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("failed to walk: %s", err)
+	}
+	if len(g.Nodes) != 2 || len(g.Edges) != 1 || g.Edges[0].Relation != RelationAlias {
+		t.Errorf("graph got=%+v", g)
+	}
+}
+
+func TestExport(t *testing.T) {
+	w, _ := newWalker(t)
+
+	g, err := w.Walk(context.Background(), 1743)
+	if err != nil {
+		t.Fatalf("failed to walk: %s", err)
+	}
+
+	var dot bytes.Buffer
+	if err := g.WriteDOT(&dot); err != nil {
+		t.Fatalf("failed to write dot: %s", err)
+	}
+	for _, line := range []string{
+		`1743 [label="Exos", style=bold];`,
+		`1743 -> 2 [label="member", color=grey];`,
+		`1 -> 239 [label="alias", style=dashed, dir=none];`,
+	} {
+		if !strings.Contains(dot.String(), line) {
+			t.Errorf("dot misses %q:\n%s", line, dot.String())
+		}
+	}
+
+	b, err := json.Marshal(g)
+	if err != nil {
+		t.Fatalf("failed to encode graph: %s", err)
+	}
+	var decoded Graph
+	if err := json.Unmarshal(b, &decoded); err != nil || len(decoded.Edges) != len(g.Edges) || decoded.Root != 1743 {
+		t.Errorf("json got=%s, %v", b, err)
+	}
+}
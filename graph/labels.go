@@ -0,0 +1,215 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ninnemana/go-discogs"
+)
+
+// LabelClient is the part of the discogs client a LabelWalker uses.
+type LabelClient interface {
+	Label(labelID int) (*discogs.Label, error)
+	LabelReleases(labelID int, pagination *discogs.Pagination) (*discogs.LabelReleases, error)
+}
+
+// LabelNode is a label of a tree and its sublabels.
+type LabelNode struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Fetched reports whether the label was fetched; sublabels beyond the
+	// maximum depth or not found are only known by the reference to them.
+	Fetched   bool                    `json:"fetched"`
+	Sublabels []*LabelNode            `json:"sublabels,omitempty"`
+	Releases  []discogs.ReleaseSource `json:"releases,omitempty"`
+}
+
+// Walk calls fn for the node and its sublabels, depth first.
+func (n *LabelNode) Walk(fn func(*LabelNode)) {
+	fn(n)
+	for _, s := range n.Sublabels {
+		s.Walk(fn)
+	}
+}
+
+// LabelTree is the family of a label: its chain of parents and the
+// sublabels of the topmost parent.
+type LabelTree struct {
+	// Label is the label the tree was built for.
+	Label int `json:"label"`
+	// Parents runs from the parent of Label up to the topmost parent.
+	Parents []discogs.LabelRef `json:"parents,omitempty"`
+	// Root is the topmost parent, or Label itself when it has none.
+	Root *LabelNode `json:"root"`
+}
+
+// LabelRelease is a release of a label family and the label it was listed on.
+type LabelRelease struct {
+	discogs.ReleaseSource
+	LabelID int `json:"label_id"`
+}
+
+// Releases returns the releases of every label of the tree, listing a
+// release found on several labels once, on the label closest to the root.
+// It is empty unless the tree was built with LabelWalker.Releases set.
+func (t *LabelTree) Releases() []LabelRelease {
+	var (
+		out  []LabelRelease
+		seen = map[int]bool{}
+	)
+	// breadth first, so parents come before their sublabels
+	queue := []*LabelNode{t.Root}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for _, r := range n.Releases {
+			if !seen[r.ID] {
+				seen[r.ID] = true
+				out = append(out, LabelRelease{ReleaseSource: r, LabelID: n.ID})
+			}
+		}
+		queue = append(queue, n.Sublabels...)
+	}
+	return out
+}
+
+// LabelWalker builds label trees. Its fields configure the walks and must
+// not be changed during one.
+type LabelWalker struct {
+	client LabelClient
+
+	// Depth limits the levels of sublabels below the root; 0 walks them all.
+	Depth int
+	// Releases fetches the releases of every label of the tree.
+	Releases bool
+	// PerPage is the page size of release lists (default is 100).
+	PerPage int
+
+	mu    sync.Mutex
+	cache map[int]*discogs.Label
+}
+
+// NewLabelWalker returns a LabelWalker fetching labels with client.
+func NewLabelWalker(client LabelClient) *LabelWalker {
+	return &LabelWalker{
+		client:  client,
+		PerPage: 100,
+		cache:   make(map[int]*discogs.Label),
+	}
+}
+
+// label returns a label from the cache or fetches it.
+func (w *LabelWalker) label(id int) (*discogs.Label, error) {
+	w.mu.Lock()
+	l, ok := w.cache[id]
+	w.mu.Unlock()
+	if ok {
+		return l, nil
+	}
+
+	l, err := w.client.Label(id)
+	if err != nil {
+		return nil, err
+	}
+	w.mu.Lock()
+	w.cache[id] = l
+	w.mu.Unlock()
+	return l, nil
+}
+
+// Tree builds the tree of a label: it follows the parent labels up to the
+// topmost one, then walks its sublabels down. A label met twice is only
+// walked the first time. Sublabels that are not found stay in the tree
+// unfetched; other errors stop the walk.
+func (w *LabelWalker) Tree(ctx context.Context, labelID int) (*LabelTree, error) {
+	t := &LabelTree{Label: labelID}
+
+	top, err := w.label(labelID)
+	if err != nil {
+		return nil, fmt.Errorf("label %d: %w", labelID, err)
+	}
+	seen := map[int]bool{labelID: true}
+	for top.ParentLabel != nil && !seen[top.ParentLabel.ID] {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		parent := *top.ParentLabel
+		seen[parent.ID] = true
+		t.Parents = append(t.Parents, parent)
+
+		l, err := w.label(parent.ID)
+		if errors.Is(err, discogs.ErrNotFound) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("label %d: %w", parent.ID, err)
+		}
+		top = l
+	}
+
+	t.Root = &LabelNode{ID: top.ID, Name: top.Name}
+	if err := w.walk(ctx, t.Root, top, 0, map[int]bool{}); err != nil {
+		return t, err
+	}
+	return t, nil
+}
+
+func (w *LabelWalker) walk(ctx context.Context, n *LabelNode, l *discogs.Label, depth int, seen map[int]bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	seen[l.ID] = true
+	n.Name = l.Name
+	n.Fetched = true
+
+	if w.Releases {
+		releases, err := w.releases(ctx, l.ID)
+		if err != nil {
+			return err
+		}
+		n.Releases = releases
+	}
+
+	for _, s := range l.Sublabels {
+		if seen[s.ID] {
+			continue
+		}
+		child := &LabelNode{ID: s.ID, Name: s.Name}
+		n.Sublabels = append(n.Sublabels, child)
+		if w.Depth > 0 && depth+1 >= w.Depth {
+			continue
+		}
+
+		sub, err := w.label(s.ID)
+		if errors.Is(err, discogs.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("label %d: %w", s.ID, err)
+		}
+		if err := w.walk(ctx, child, sub, depth+1, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// releases pages through the releases of a label.
+func (w *LabelWalker) releases(ctx context.Context, labelID int) ([]discogs.ReleaseSource, error) {
+	var out []discogs.ReleaseSource
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		resp, err := w.client.LabelReleases(labelID, &discogs.Pagination{Page: page, PerPage: w.PerPage})
+		if err != nil {
+			return nil, fmt.Errorf("label %d releases: %w", labelID, err)
+		}
+		out = append(out, resp.Releases...)
+		if page >= resp.Pagination.Pages {
+			return out, nil
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ninnemana/go-discogs"
+	"github.com/ninnemana/go-discogs/discogstest"
+)
+
+func newLabelWalker(t *testing.T) *LabelWalker {
+	t.Helper()
+	s := discogstest.NewTestServer(t)
+	s.AddLabel(discogs.Label{ID: 1, Name: "Major",
+		Sublabels: []discogs.Sublable{{ID: 2, Name: "Imprint"}, {ID: 3, Name: "Other Imprint"}},
+	}, discogs.ReleaseSource{ID: 100, Title: "Major Release"}, discogs.ReleaseSource{ID: 200, Title: "Shared Release"})
+	s.AddLabel(discogs.Label{ID: 2, Name: "Imprint",
+		ParentLabel: &discogs.LabelRef{ID: 1, Name: "Major"},
+		Sublabels:   []discogs.Sublable{{ID: 4, Name: "Series"}, {ID: 1, Name: "Major"}},
+	}, discogs.ReleaseSource{ID: 200, Title: "Shared Release"}, discogs.ReleaseSource{ID: 201, Title: "Imprint Release"})
+	s.AddLabel(discogs.Label{ID: 4, Name: "Series",
+		ParentLabel: &discogs.LabelRef{ID: 2, Name: "Imprint"},
+	}, discogs.ReleaseSource{ID: 400, Title: "Series Release"})
+
+	w := NewLabelWalker(s.NewClient(t, ""))
+	w.PerPage = 1
+	return w
+}
+
+func TestLabelTree(t *testing.T) {
+	w := newLabelWalker(t)
+	w.Releases = true
+
+	tree, err := w.Tree(context.Background(), 4)
+	if err != nil {
+		t.Fatalf("failed to build tree: %s", err)
+	}
+	if len(tree.Parents) != 2 || tree.Parents[0].ID != 2 || tree.Parents[1].ID != 1 {
+		t.Errorf("parents got=%+v", tree.Parents)
+	}
+	if tree.Root.ID != 1 || len(tree.Root.Sublabels) != 2 {
+		t.Fatalf("root got=%+v", tree.Root)
+	}
+
+	var ids []int
+	tree.Root.Walk(func(n *LabelNode) { ids = append(ids, n.ID) })
+	if len(ids) != 4 || ids[1] != 2 || ids[2] != 4 || ids[3] != 3 {
+		t.Errorf("walk got=%v", ids)
+	}
+	if other := tree.Root.Sublabels[1]; other.Fetched || other.Name != "Other Imprint" {
+		t.Errorf("missing sublabel got=%+v", other)
+	}
+
+	releases := tree.Releases()
+	want := map[int]int{100: 1, 200: 1, 201: 2, 400: 4}
+	if len(releases) != len(want) {
+		t.Fatalf("releases got=%+v", releases)
+	}
+	for _, r := range releases {
+		if want[r.ID] != r.LabelID {
+			t.Errorf("release %d on label %d; want %d", r.ID, r.LabelID, want[r.ID])
+		}
+	}
+}
+
+func TestLabelTreeDepth(t *testing.T) {
+	w := newLabelWalker(t)
+	w.Depth = 1
+
+	tree, err := w.Tree(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("failed to build tree: %s", err)
+	}
+	if len(tree.Parents) != 0 || len(tree.Root.Sublabels) != 2 {
+		t.Fatalf("tree got=%+v", tree.Root)
+	}
+	if imprint := tree.Root.Sublabels[0]; imprint.Fetched || len(imprint.Sublabels) != 0 {
+		t.Errorf("depth limited sublabel got=%+v", imprint)
+	}
+	if len(tree.Releases()) != 0 {
+		t.Errorf("releases fetched without Releases")
+	}
+}
@@ -0,0 +1,80 @@
+package discogs
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"go.opencensus.io/trace"
+)
+
+// ImageService is an interface to download images.
+type ImageService interface {
+	// Image downloads the image at uri, like Image.URI or Image.URI150.
+	// Discogs image URLs require the User-Agent of the client, which is sent
+	// through the configured HTTP client; the token is never sent, as images
+	// are served from another host than the API.
+	//
+	// A rate limited download fails with ErrTooManyRequests and is not
+	// retried: backing off is left to the caller, like images.Fetcher does.
+	Image(ctx context.Context, uri string) ([]byte, error)
+}
+
+type imageService struct {
+	client *client
+}
+
+func newImageService(c *client) ImageService {
+	return &imageService{
+		client: c,
+	}
+}
+
+func (s *imageService) Image(ctx context.Context, uri string) ([]byte, error) {
+	ctx, span := trace.StartSpan(ctx, "ninnemana.discogs.Image")
+	defer span.End()
+
+	span.AddAttributes(trace.StringAttribute("uri", uri))
+
+	data, err := s.image(ctx, uri)
+	if err != nil {
+		span.SetStatus(trace.Status{
+			Code: trace.StatusCodeInternal,
+		})
+		span.AddAttributes(trace.StringAttribute("err", err.Error()))
+
+		return nil, err
+	}
+	return data, nil
+}
+
+func (s *imageService) image(ctx context.Context, uri string) ([]byte, error) {
+	r, err := http.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	r = r.WithContext(ctx)
+	r.Header.Set("User-Agent", s.client.header.Get("User-Agent"))
+
+	response, err := s.client.http.Do(r)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		switch response.StatusCode {
+		case http.StatusUnauthorized:
+			return nil, ErrUnauthorized
+		case http.StatusNotFound:
+			return nil, ErrNotFound
+		case http.StatusTooManyRequests:
+			return nil, ErrTooManyRequests
+		default:
+			return nil, fmt.Errorf("unknown error: %s", response.Status)
+		}
+	}
+
+	return ioutil.ReadAll(response.Body)
+}
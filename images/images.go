@@ -0,0 +1,230 @@
+// Package images downloads release, artist and label images into a
+// content-addressed store.
+//
+// Downloads go through the client, so they use its HTTP client and the
+// User-Agent Discogs requires, and are spaced out to stay within the rate limit:
+//
+//	f := images.New(client, images.DirStore("covers"))
+//	img, ok := images.Primary(release.Images)
+//	if ok {
+//		stored, err := f.Fetch(ctx, img, false)
+//		fmt.Println(stored.Key, err)
+//	}
+package images
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ninnemana/go-discogs"
+)
+
+// Image types.
+const (
+	TypePrimary   = "primary"
+	TypeSecondary = "secondary"
+)
+
+// ThumbWidth is the width of the URI150 variant of an image.
+const ThumbWidth = 150
+
+// Primary returns the primary image, or the first image when none is primary.
+func Primary(images []discogs.Image) (discogs.Image, bool) {
+	for _, img := range images {
+		if img.Type == TypePrimary {
+			return img, true
+		}
+	}
+	if len(images) > 0 {
+		return images[0], true
+	}
+	return discogs.Image{}, false
+}
+
+// Secondary returns the secondary images.
+func Secondary(images []discogs.Image) []discogs.Image {
+	var out []discogs.Image
+	for _, img := range images {
+		if img.Type == TypeSecondary {
+			out = append(out, img)
+		}
+	}
+	return out
+}
+
+// Largest returns the image with the most pixels, the primary image winning ties.
+func Largest(images []discogs.Image) (discogs.Image, bool) {
+	var (
+		best  discogs.Image
+		found bool
+	)
+	for _, img := range images {
+		if !found || better(img, best, img.Width*img.Height, best.Width*best.Height) {
+			best, found = img, true
+		}
+	}
+	return best, found
+}
+
+// Select returns the smallest image at least width by height pixels, the
+// primary image winning ties. When no image is large enough it returns the
+// largest one.
+func Select(images []discogs.Image, width, height int) (discogs.Image, bool) {
+	var (
+		best  discogs.Image
+		found bool
+	)
+	for _, img := range images {
+		if img.Width < width || img.Height < height {
+			continue
+		}
+		// negated sizes rank the smaller image first
+		if !found || better(img, best, -img.Width*img.Height, -best.Width*best.Height) {
+			best, found = img, true
+		}
+	}
+	if found {
+		return best, true
+	}
+	return Largest(images)
+}
+
+// better reports whether a ranks before b, given their sizes in the order wanted.
+func better(a, b discogs.Image, sizeA, sizeB int) bool {
+	if sizeA != sizeB {
+		return sizeA > sizeB
+	}
+	return a.Type == TypePrimary && b.Type != TypePrimary
+}
+
+// URI returns the URI of the variant of an image to show at most width pixels
+// wide: the 150 pixel thumbnail when it is large enough, otherwise the full image.
+func URI(img discogs.Image, width int) string {
+	if width > 0 && width <= ThumbWidth && img.URI150 != "" {
+		return img.URI150
+	}
+	return img.URI
+}
+
+// Stored is a downloaded image.
+type Stored struct {
+	Image discogs.Image `json:"image"`
+	URI   string        `json:"uri"`
+	Key   string        `json:"key"`
+	Size  int           `json:"size"`
+}
+
+// Fetcher downloads images into a Store. It is safe for concurrent use;
+// downloads are serialized to honour the rate limit.
+type Fetcher struct {
+	client discogs.ImageService
+	store  Store
+
+	// Interval is the least time between two downloads (default is one second).
+	Interval time.Duration
+	// Retries is the number of retries of a rate limited download (default is 3).
+	Retries int
+	// Backoff is the wait before retrying a rate limited download (default is one minute).
+	Backoff time.Duration
+
+	mu   sync.Mutex
+	last time.Time
+	keys map[string]string
+}
+
+// New returns a Fetcher downloading with client into store.
+func New(client discogs.ImageService, store Store) *Fetcher {
+	return &Fetcher{
+		client:   client,
+		store:    store,
+		Interval: time.Second,
+		Retries:  3,
+		Backoff:  time.Minute,
+		keys:     make(map[string]string),
+	}
+}
+
+// Fetch downloads an image, or its thumbnail, and stores it.
+// A URI already fetched by this Fetcher is not downloaded again.
+func (f *Fetcher) Fetch(ctx context.Context, img discogs.Image, thumb bool) (Stored, error) {
+	uri := img.URI
+	if thumb && img.URI150 != "" {
+		uri = img.URI150
+	}
+	if uri == "" {
+		return Stored{}, discogs.ErrNotFound
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if key, ok := f.keys[uri]; ok {
+		data, err := f.store.Get(key)
+		if err == nil {
+			return Stored{Image: img, URI: uri, Key: key, Size: len(data)}, nil
+		}
+		if !errors.Is(err, ErrNotStored) {
+			return Stored{}, err
+		}
+	}
+
+	data, err := f.download(ctx, uri)
+	if err != nil {
+		return Stored{}, err
+	}
+
+	key := Key(data)
+	if err := f.store.Put(key, data); err != nil {
+		return Stored{}, err
+	}
+	f.keys[uri] = key
+	return Stored{Image: img, URI: uri, Key: key, Size: len(data)}, nil
+}
+
+// FetchAll fetches images in order and stops at the first error,
+// returning the images stored so far.
+func (f *Fetcher) FetchAll(ctx context.Context, images []discogs.Image, thumb bool) ([]Stored, error) {
+	var out []Stored
+	for _, img := range images {
+		s, err := f.Fetch(ctx, img, thumb)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// download waits for the interval since the last download and retries
+// rate limited downloads after the backoff.
+func (f *Fetcher) download(ctx context.Context, uri string) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		if err := wait(ctx, time.Until(f.last.Add(f.Interval))); err != nil {
+			return nil, err
+		}
+		data, err := f.client.Image(ctx, uri)
+		f.last = time.Now()
+		if !errors.Is(err, discogs.ErrTooManyRequests) || attempt >= f.Retries {
+			return data, err
+		}
+		if err := wait(ctx, f.Backoff); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,190 @@
+package images
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ninnemana/go-discogs"
+	"github.com/ninnemana/go-discogs/discogstest"
+)
+
+// jpeg is the start of a JPEG file, enough for content sniffing.
+var jpeg = []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00")
+
+func TestSelect(t *testing.T) {
+	images := []discogs.Image{
+		{Type: TypeSecondary, Width: 300, Height: 300, URI: "s300"},
+		{Type: TypePrimary, Width: 600, Height: 600, URI: "p600"},
+		{Type: TypeSecondary, Width: 600, Height: 600, URI: "s600"},
+		{Type: TypeSecondary, Width: 1200, Height: 900, URI: "s1200"},
+	}
+
+	tests := map[string]struct {
+		width, height int
+		want          string
+	}{
+		"smallest":  {0, 0, "s300"},
+		"tie":       {500, 500, "p600"},
+		"large":     {1000, 800, "s1200"},
+		"too large": {5000, 5000, "s1200"},
+		"tall":      {100, 700, "s1200"},
+	}
+	for name, tt := range tests {
+		got, ok := Select(images, tt.width, tt.height)
+		if !ok || got.URI != tt.want {
+			t.Errorf("%s: got=%q; want=%q", name, got.URI, tt.want)
+		}
+	}
+
+	if p, _ := Primary(images); p.URI != "p600" {
+		t.Errorf("primary got=%q", p.URI)
+	}
+	if s := Secondary(images); len(s) != 3 {
+		t.Errorf("secondary got=%d", len(s))
+	}
+	if _, ok := Select(nil, 0, 0); ok {
+		t.Errorf("select of no images succeeded")
+	}
+	if uri := URI(discogs.Image{URI: "full", URI150: "thumb"}, 100); uri != "thumb" {
+		t.Errorf("uri got=%q", uri)
+	}
+}
+
+func TestFetcher(t *testing.T) {
+	s := discogstest.NewServer()
+	defer s.Close()
+
+	img := discogs.Image{Type: TypePrimary, URI: s.AddImage("cover.jpg", jpeg), URI150: s.AddImage("cover-150.jpg", jpeg[:4])}
+	same := discogs.Image{Type: TypeSecondary, URI: s.AddImage("copy.jpg", jpeg)}
+
+	client, err := discogs.New(s.Options())
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+
+	store := &MemoryStore{}
+	f := New(client, store)
+	f.Interval = 0
+
+	stored, err := f.FetchAll(context.Background(), []discogs.Image{img, same, img}, false)
+	if err != nil {
+		t.Fatalf("failed to fetch: %s", err)
+	}
+	if len(stored) != 3 || stored[0].Key != Key(jpeg) || stored[1].Key != stored[0].Key || stored[0].Size != len(jpeg) {
+		t.Errorf("stored got=%+v", stored)
+	}
+	if stored[0].Key[len(stored[0].Key)-4:] != ".jpg" {
+		t.Errorf("key got=%q", stored[0].Key)
+	}
+
+	thumb, err := f.Fetch(context.Background(), img, true)
+	if err != nil || thumb.URI != img.URI150 || thumb.Key == stored[0].Key {
+		t.Errorf("thumb got=%+v, %v", thumb, err)
+	}
+
+	_, err = f.Fetch(context.Background(), discogs.Image{URI: s.URL + "/images/missing.jpg"}, false)
+	if !errors.Is(err, discogs.ErrNotFound) {
+		t.Errorf("missing err got=%v", err)
+	}
+}
+
+func TestFetcherRateLimit(t *testing.T) {
+	s := discogstest.NewServer()
+	defer s.Close()
+	s.RateLimit = 1
+
+	uri := s.AddImage("cover.jpg", jpeg)
+	client, err := discogs.New(s.Options())
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+
+	f := New(client, &MemoryStore{})
+	f.Interval = 0
+	f.Backoff = 0
+	f.Retries = 1
+
+	if _, err := f.Fetch(context.Background(), discogs.Image{URI: s.URL + "/images/other.jpg"}, false); !errors.Is(err, discogs.ErrNotFound) {
+		t.Fatalf("first fetch err got=%v", err)
+	}
+	if _, err := f.Fetch(context.Background(), discogs.Image{URI: uri}, false); !errors.Is(err, discogs.ErrTooManyRequests) {
+		t.Errorf("rate limited fetch err got=%v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	f.Backoff = time.Second
+	if _, err := f.Fetch(ctx, discogs.Image{URI: uri}, false); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled fetch err got=%v", err)
+	}
+}
+
+func TestFetcherHeaders(t *testing.T) {
+	var calls int
+	host := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("image host got Authorization %q", auth)
+		}
+		if ua := r.Header.Get("User-Agent"); ua != "TestUserAgent" {
+			t.Errorf("image host got User-Agent %q", ua)
+		}
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write(jpeg)
+	}))
+	defer host.Close()
+
+	client, err := discogs.New(&discogs.Options{UserAgent: "TestUserAgent", Token: "secret"})
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+
+	f := New(client, &MemoryStore{})
+	f.Interval = 0
+	f.Backoff = 0
+	f.Retries = 1
+
+	stored, err := f.Fetch(context.Background(), discogs.Image{URI: host.URL + "/cover.jpg"}, false)
+	if err != nil || stored.Key != Key(jpeg) {
+		t.Errorf("fetch got=%+v, %v", stored, err)
+	}
+	if calls != 2 {
+		t.Errorf("calls got=%d; want=2", calls)
+	}
+}
+
+func TestDirStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "images")
+	if err != nil {
+		t.Fatalf("failed to create dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	store := DirStore(dir)
+	key := Key(jpeg)
+	if ok, err := store.Has(key); ok || err != nil {
+		t.Fatalf("has got=%t, %v", ok, err)
+	}
+	if err := store.Put(key, jpeg); err != nil {
+		t.Fatalf("failed to put: %s", err)
+	}
+	if err := store.Put(key, jpeg); err != nil {
+		t.Fatalf("failed to put again: %s", err)
+	}
+	data, err := store.Get(key)
+	if err != nil || string(data) != string(jpeg) {
+		t.Errorf("get got=%q, %v", data, err)
+	}
+	if _, err := store.Get("missing"); !errors.Is(err, ErrNotStored) {
+		t.Errorf("missing err got=%v", err)
+	}
+}
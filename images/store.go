@@ -0,0 +1,141 @@
+package images
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrNotStored is returned by a Store for keys it does not hold.
+var ErrNotStored = errors.New("images: not stored")
+
+// Store keeps image data under content-addressed keys, so an image
+// downloaded twice, or shared by releases, is stored once.
+type Store interface {
+	Put(key string, data []byte) error
+	Get(key string) ([]byte, error)
+	Has(key string) (bool, error)
+}
+
+// Key returns the content address of image data: the hex SHA-256 of the data
+// followed by the extension of its content type, like ".jpg".
+func Key(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]) + extension(data)
+}
+
+func extension(data []byte) string {
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	case "image/webp":
+		return ".webp"
+	default:
+		return ""
+	}
+}
+
+// DirStore stores images as files in a directory, in sub directories named
+// after the first two characters of their key.
+type DirStore string
+
+func (d DirStore) path(key string) string {
+	if len(key) < 2 {
+		return filepath.Join(string(d), key)
+	}
+	return filepath.Join(string(d), key[:2], key)
+}
+
+// Put writes the image data, unless the key is already stored.
+func (d DirStore) Put(key string, data []byte) error {
+	if ok, err := d.Has(key); err != nil || ok {
+		return err
+	}
+
+	path := d.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	// write to a temporary file first so readers never see partial images
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get reads the image data of a key.
+func (d DirStore) Get(key string) ([]byte, error) {
+	data, err := ioutil.ReadFile(d.path(key))
+	if os.IsNotExist(err) {
+		return nil, ErrNotStored
+	}
+	return data, err
+}
+
+// Has reports whether the key is stored.
+func (d DirStore) Has(key string) (bool, error) {
+	_, err := os.Stat(d.path(key))
+	switch {
+	case err == nil:
+		return true, nil
+	case os.IsNotExist(err):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// MemoryStore stores images in memory. The zero value is ready to use.
+type MemoryStore struct {
+	mu     sync.RWMutex
+	images map[string][]byte
+}
+
+// Put stores the image data.
+func (m *MemoryStore) Put(key string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.images == nil {
+		m.images = make(map[string][]byte)
+	}
+	m.images[key] = data
+	return nil
+}
+
+// Get returns the image data of a key.
+func (m *MemoryStore) Get(key string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data, ok := m.images[key]
+	if !ok {
+		return nil, ErrNotStored
+	}
+	return data, nil
+}
+
+// Has reports whether the key is stored.
+func (m *MemoryStore) Has(key string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.images[key]
+	return ok, nil
+}
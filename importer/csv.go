@@ -0,0 +1,139 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Row is a collection item to import.
+// A row is matched by ReleaseID, or else by Barcode, or else by Catno and Label.
+type Row struct {
+	// Record is the 1-based number of the row's record in the CSV file,
+	// counting the header as record 1, like the row numbers of a spreadsheet.
+	// It is not a line number: a quoted field may span several lines.
+	Record    int
+	ReleaseID int
+	Catno     string
+	Label     string
+	Barcode   string
+	// Folder is the folder name, the default is Uncategorized.
+	Folder string
+	Rating int
+	// Fields holds custom field values by field name, including Notes.
+	Fields map[string]string
+}
+
+type column int
+
+const (
+	columnIgnored column = iota
+	columnReleaseID
+	columnCatno
+	columnLabel
+	columnBarcode
+	columnFolder
+	columnRating
+	columnField
+)
+
+// columns maps lower case header names to columns. The names of the
+// discogs.com collection export are included, so exports can be imported as is.
+var columns = map[string]column{
+	"release_id":       columnReleaseID,
+	"release id":       columnReleaseID,
+	"id":               columnReleaseID,
+	"catno":            columnCatno,
+	"catalog#":         columnCatno,
+	"catalog number":   columnCatno,
+	"label":            columnLabel,
+	"barcode":          columnBarcode,
+	"upc":              columnBarcode,
+	"ean":              columnBarcode,
+	"folder":           columnFolder,
+	"collectionfolder": columnFolder,
+	"rating":           columnRating,
+	"artist":           columnIgnored,
+	"title":            columnIgnored,
+	"format":           columnIgnored,
+	"released":         columnIgnored,
+	"date added":       columnIgnored,
+}
+
+// ReadRows reads rows from CSV with a header line. Columns that are not
+// recognized are custom fields; a "Collection " prefix is removed from their
+// names, matching the discogs.com export.
+func ReadRows(r io.Reader) ([]Row, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	kinds := make([]column, len(header))
+	names := make([]string, len(header))
+	for i, h := range header {
+		h = strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))
+		kind, ok := columns[strings.ToLower(h)]
+		if !ok {
+			kind = columnField
+			h = strings.TrimPrefix(h, "Collection ")
+		}
+		kinds[i] = kind
+		names[i] = h
+	}
+
+	var rows []Row
+	for record := 2; ; record++ {
+		fields, err := cr.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		row := Row{Record: record}
+		for i, value := range fields {
+			if i >= len(kinds) {
+				break
+			}
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+
+			switch kinds[i] {
+			case columnReleaseID:
+				if row.ReleaseID, err = strconv.Atoi(value); err != nil {
+					return nil, fmt.Errorf("importer: record %d: invalid release id %q", record, value)
+				}
+			case columnCatno:
+				row.Catno = value
+			case columnLabel:
+				row.Label = value
+			case columnBarcode:
+				row.Barcode = value
+			case columnFolder:
+				row.Folder = value
+			case columnRating:
+				if row.Rating, err = strconv.Atoi(value); err != nil || row.Rating < 0 || row.Rating > 5 {
+					return nil, fmt.Errorf("importer: record %d: invalid rating %q", record, value)
+				}
+			case columnField:
+				if row.Fields == nil {
+					row.Fields = make(map[string]string)
+				}
+				row.Fields[names[i]] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+}
@@ -0,0 +1,271 @@
+// Package importer adds releases listed in a CSV file to a user's Discogs collection.
+//
+// Rows are matched by release id, or searched by barcode or by catalog number
+// and label. Missing folders are created and ratings and custom field values
+// are set on the added instances.
+//
+// An Importer runs dry by default: it matches every row and reports what it
+// would do without changing the collection. If an import is interrupted, for
+// instance by the rate limit, the returned report can be passed back in
+// Importer.Resume to continue where it stopped.
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ninnemana/go-discogs"
+)
+
+// Client is the part of the discogs client used by the importer.
+type Client interface {
+	discogs.CollectionService
+	discogs.SearchService
+}
+
+// Status is the outcome of importing a row.
+type Status string
+
+// Row statuses.
+const (
+	// StatusAdded means the release was added to the collection.
+	StatusAdded Status = "added"
+	// StatusPlanned means the release was matched in a dry run.
+	StatusPlanned Status = "planned"
+	// StatusUnmatched means no single release matched the row.
+	StatusUnmatched Status = "unmatched"
+	// StatusFailed means the row could not be imported and may be retried.
+	StatusFailed Status = "failed"
+)
+
+// Result is the outcome of importing a row.
+type Result struct {
+	Record     int    `json:"record"`
+	ReleaseID  int    `json:"release_id,omitempty"`
+	Folder     string `json:"folder"`
+	InstanceID int    `json:"instance_id,omitempty"`
+	Status     Status `json:"status"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// Report describes an import.
+type Report struct {
+	Results []Result `json:"results"`
+	// Folders lists the folders created, or to be created in a dry run.
+	Folders []string `json:"folders,omitempty"`
+	// UnknownFields lists custom field names without a matching collection field.
+	UnknownFields []string `json:"unknown_fields,omitempty"`
+}
+
+// Unmatched returns the results of rows that did not match a release.
+func (r *Report) Unmatched() []Result {
+	var out []Result
+	for _, res := range r.Results {
+		if res.Status == StatusUnmatched {
+			out = append(out, res)
+		}
+	}
+	return out
+}
+
+// done reports whether the row of record was completed by a previous run.
+func (r *Report) done(record int) (Result, bool) {
+	if r == nil {
+		return Result{}, false
+	}
+	for _, res := range r.Results {
+		if res.Record == record && (res.Status == StatusAdded || res.Status == StatusUnmatched) {
+			return res, true
+		}
+	}
+	return Result{}, false
+}
+
+// defaultFolder is the folder of rows without a folder name.
+const defaultFolder = "Uncategorized"
+
+// Importer adds rows to a user's collection.
+type Importer struct {
+	client  Client
+	options []discogs.Option
+
+	// DryRun matches rows without changing the collection (default is true).
+	DryRun bool
+	// Resume is the report of an interrupted import; rows it completed are skipped.
+	Resume *Report
+}
+
+// New returns an importer using client. options are passed to every
+// collection request, typically discogs.WithClient and discogs.WithCredentials.
+func New(client Client, options ...discogs.Option) *Importer {
+	return &Importer{
+		client:  client,
+		options: options,
+		DryRun:  true,
+	}
+}
+
+// Import adds rows to the collection of username. The report is returned even
+// if an error interrupts the import; rows not reached are not in the report.
+func (i *Importer) Import(ctx context.Context, username string, rows []Row) (*Report, error) {
+	report := &Report{}
+
+	folders, err := i.client.GetFolders(ctx, username, i.options...)
+	if err != nil {
+		return report, err
+	}
+	folderIDs := make(map[string]int, len(folders.Folders))
+	for _, f := range folders.Folders {
+		folderIDs[strings.ToLower(f.Name)] = f.ID
+	}
+
+	fields, err := i.client.GetCollectionFields(ctx, username, i.options...)
+	if err != nil {
+		return report, err
+	}
+	fieldIDs := make(map[string]int, len(fields.Fields))
+	for _, f := range fields.Fields {
+		fieldIDs[strings.ToLower(f.Name)] = f.ID
+	}
+
+	unknown := make(map[string]bool)
+	for _, row := range rows {
+		for name := range row.Fields {
+			if _, ok := fieldIDs[strings.ToLower(name)]; !ok {
+				unknown[name] = true
+			}
+		}
+	}
+	for name := range unknown {
+		report.UnknownFields = append(report.UnknownFields, name)
+	}
+	sort.Strings(report.UnknownFields)
+
+	for _, row := range rows {
+		if res, ok := i.Resume.done(row.Record); ok {
+			report.Results = append(report.Results, res)
+			continue
+		}
+
+		res := Result{Record: row.Record, Folder: row.Folder, Status: StatusFailed}
+		if res.Folder == "" {
+			res.Folder = defaultFolder
+		}
+
+		err := i.importRow(ctx, username, row, &res, report, folderIDs, fieldIDs)
+		if err != nil {
+			res.Reason = err.Error()
+		}
+		report.Results = append(report.Results, res)
+
+		if errors.Is(err, discogs.ErrTooManyRequests) || errors.Is(err, discogs.ErrUnauthorized) || ctx.Err() != nil {
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			return report, fmt.Errorf("importer: interrupted at record %d: %w", row.Record, err)
+		}
+	}
+
+	return report, nil
+}
+
+func (i *Importer) importRow(ctx context.Context, username string, row Row, res *Result, report *Report, folderIDs, fieldIDs map[string]int) error {
+	releaseID, reason, err := i.match(ctx, row)
+	if err != nil {
+		return err
+	}
+	if releaseID == 0 {
+		res.Status = StatusUnmatched
+		res.Reason = reason
+		return nil
+	}
+	res.ReleaseID = releaseID
+
+	folderID, ok := folderIDs[strings.ToLower(res.Folder)]
+	if !ok {
+		report.Folders = append(report.Folders, res.Folder)
+		if !i.DryRun {
+			folder, err := i.client.CreateFolder(ctx, username, res.Folder, i.options...)
+			if err != nil {
+				return err
+			}
+			folderID = folder.ID
+		}
+		folderIDs[strings.ToLower(res.Folder)] = folderID
+	}
+
+	if i.DryRun {
+		res.Status = StatusPlanned
+		return nil
+	}
+
+	instance, err := i.client.AddToFolder(ctx, username, folderID, releaseID, i.options...)
+	if err != nil {
+		return err
+	}
+	res.InstanceID = instance.InstanceID
+	res.Status = StatusAdded
+
+	// The instance exists from here on: failures to set its details are
+	// reported but leave the row added, so a resumed import does not add it twice.
+	if row.Rating > 0 {
+		if err := i.client.SetInstanceRating(ctx, username, folderID, releaseID, instance.InstanceID, row.Rating, i.options...); err != nil {
+			res.Reason = "failed to set rating: " + err.Error()
+		}
+	}
+
+	names := make([]string, 0, len(row.Fields))
+	for name := range row.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fieldID, ok := fieldIDs[strings.ToLower(name)]
+		if !ok {
+			continue
+		}
+		if err := i.client.SetInstanceField(ctx, username, folderID, releaseID, instance.InstanceID, fieldID, row.Fields[name], i.options...); err != nil {
+			res.Reason = "failed to set field " + name + ": " + err.Error()
+		}
+	}
+	return nil
+}
+
+// match returns the release id of row, or 0 and the reason no single release matched.
+func (i *Importer) match(ctx context.Context, row Row) (int, string, error) {
+	if row.ReleaseID != 0 {
+		return row.ReleaseID, "", nil
+	}
+
+	req := discogs.SearchRequest{Type: discogs.SearchTypeRelease}
+	switch {
+	case row.Barcode != "":
+		req.Barcode = strings.NewReplacer(" ", "", "-", "").Replace(row.Barcode)
+	case row.Catno != "":
+		req.Catno = row.Catno
+		req.Label = row.Label
+	default:
+		return 0, "no release id, barcode or catalog number", nil
+	}
+
+	search, err := i.client.SearchContext(ctx, req, i.options...)
+	if err != nil {
+		return 0, "", err
+	}
+
+	ids := make(map[int]bool)
+	for _, r := range search.Results {
+		ids[r.ID] = true
+	}
+	switch len(ids) {
+	case 0:
+		return 0, "no release found", nil
+	case 1:
+		return search.Results[0].ID, "", nil
+	default:
+		return 0, fmt.Sprintf("ambiguous: %d releases found", search.Pagination.Items), nil
+	}
+}
@@ -0,0 +1,202 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gomodule/oauth1/oauth"
+	"github.com/ninnemana/go-discogs"
+	"github.com/ninnemana/go-discogs/discogstest"
+)
+
+const testCSV = `Catalog#,Artist,Title,Label,Format,Rating,Released,release_id,CollectionFolder,Date Added,Collection Media Condition,Collection Notes
+MLR-007,St. Petersburg Ska-Jazz Review,Elephant Riddim,Magnetic Loft Records,Vinyl,5,2016,8138518,Ska,,Mint (M),first copy
+,,,,,,,,,,,
+,,,,,,,,Ska,,,
+`
+
+func TestReadRows(t *testing.T) {
+	rows, err := ReadRows(strings.NewReader("barcode,Folder,Rating,Collection Notes,Extra\n4 607 16 000023 5,Ska,4,signed,x\n"))
+	if err != nil {
+		t.Fatalf("failed to read rows: %s", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("rows got=%d; want=1", len(rows))
+	}
+
+	row := rows[0]
+	if row.Record != 2 || row.Barcode != "4 607 16 000023 5" || row.Folder != "Ska" || row.Rating != 4 {
+		t.Errorf("row got=%+v", row)
+	}
+	if row.Fields["Notes"] != "signed" || row.Fields["Extra"] != "x" {
+		t.Errorf("fields got=%v", row.Fields)
+	}
+
+	// a quoted field spanning lines is one record
+	rows, err = ReadRows(strings.NewReader("release_id,Notes\n1,\"two\nlines\"\n2,\n"))
+	if err != nil {
+		t.Fatalf("failed to read rows: %s", err)
+	}
+	if len(rows) != 2 || rows[1].Record != 3 || rows[1].ReleaseID != 2 || rows[0].Fields["Notes"] != "two\nlines" {
+		t.Errorf("rows got=%+v", rows)
+	}
+
+	if _, err := ReadRows(strings.NewReader("release_id,rating\n1,6\n")); err == nil {
+		t.Error("expected an error for an invalid rating")
+	}
+}
+
+func newImporter(t *testing.T) (*Importer, *discogstest.Server) {
+	t.Helper()
+	s := discogstest.NewTestServer(t)
+	s.AddFolder("someuser", discogs.Folder{ID: 0, Name: "All"})
+	s.AddFolder("someuser", discogs.Folder{ID: 1, Name: "Uncategorized"})
+	s.AddCollectionField("someuser", discogs.Field{ID: 1, Name: "Media Condition", Type: "dropdown"})
+	s.AddCollectionField("someuser", discogs.Field{ID: 3, Name: "Notes", Type: "textarea"})
+	s.AddRelease(discogs.Release{
+		ID:          8138518,
+		Title:       "Elephant Riddim",
+		Labels:      []discogs.LabelSource{{Name: "Magnetic Loft Records", Catno: "MLR-007"}},
+		Identifiers: []discogs.Identifier{{Type: "Barcode", Value: "4607160000235"}},
+	})
+	s.AddRelease(discogs.Release{
+		ID:     2,
+		Title:  "Other",
+		Labels: []discogs.LabelSource{{Name: "Magnetic Loft Records", Catno: "MLR-008"}},
+	})
+	s.AddRelease(discogs.Release{
+		ID:     3,
+		Title:  "Other Pressing",
+		Labels: []discogs.LabelSource{{Name: "Magnetic Loft Records", Catno: "MLR-008"}},
+	})
+
+	// no token: every request, searches included, is signed with OAuth
+	i := New(s.NewClient(t, ""),
+		discogs.WithClient(&oauth.Client{}),
+		discogs.WithCredentials(&oauth.Credentials{Token: "token", Secret: "secret"}),
+	)
+	return i, s
+}
+
+func TestImport(t *testing.T) {
+	i, s := newImporter(t)
+
+	rows := []Row{
+		{Record: 2, ReleaseID: 8138518, Folder: "Ska", Rating: 5, Fields: map[string]string{"Media Condition": "Mint (M)", "Notes": "first copy"}},
+		{Record: 3, Barcode: "4 607160 000235"},
+		{Record: 4, Catno: "MLR-008", Label: "Magnetic Loft"},
+		{Record: 5, Catno: "NOPE-1"},
+		{Record: 6, Folder: "ska", Fields: map[string]string{"Grading": "VG"}},
+	}
+
+	// A dry run does not change the collection.
+	report, err := i.Import(context.Background(), "someuser", rows)
+	if err != nil {
+		t.Fatalf("failed to import: %s", err)
+	}
+	if len(s.CollectionItems("someuser")) != 0 || len(s.Folders("someuser")) != 2 {
+		t.Error("dry run changed the collection")
+	}
+	want := []Status{StatusPlanned, StatusPlanned, StatusUnmatched, StatusUnmatched, StatusUnmatched}
+	for n, res := range report.Results {
+		if res.Status != want[n] {
+			t.Errorf("record %d status got=%s; want=%s", res.Record, res.Status, want[n])
+		}
+	}
+	if len(report.Folders) != 1 || report.Folders[0] != "Ska" {
+		t.Errorf("folders got=%v; want=[Ska]", report.Folders)
+	}
+	if len(report.UnknownFields) != 1 || report.UnknownFields[0] != "Grading" {
+		t.Errorf("unknown fields got=%v; want=[Grading]", report.UnknownFields)
+	}
+	if u := report.Unmatched(); len(u) != 3 || !strings.HasPrefix(u[0].Reason, "ambiguous") {
+		t.Errorf("unmatched got=%+v", u)
+	}
+
+	i.DryRun = false
+	report, err = i.Import(context.Background(), "someuser", rows)
+	if err != nil {
+		t.Fatalf("failed to import: %s", err)
+	}
+	if report.Results[0].Status != StatusAdded || report.Results[1].Status != StatusAdded {
+		t.Errorf("results got=%+v", report.Results)
+	}
+
+	items := s.CollectionItems("someuser")
+	if len(items) != 2 {
+		t.Fatalf("items got=%d; want=2", len(items))
+	}
+	folders := s.Folders("someuser")
+	if len(folders) != 3 || folders[2].Name != "Ska" {
+		t.Fatalf("folders got=%+v", folders)
+	}
+	if items[0].FolderID != folders[2].ID || items[0].Rating != 5 || len(items[0].Notes) != 2 {
+		t.Errorf("first item got=%+v", items[0])
+	}
+	if items[1].FolderID != 1 {
+		t.Errorf("second item folder got=%d; want=1", items[1].FolderID)
+	}
+}
+
+func TestImportBarcodesWithoutToken(t *testing.T) {
+	i, s := newImporter(t)
+	i.DryRun = false
+
+	rows, err := ReadRows(strings.NewReader("Barcode,Folder\n4 607160 000235,Ska\n0000000000000,Ska\n"))
+	if err != nil {
+		t.Fatalf("failed to read rows: %s", err)
+	}
+	report, err := i.Import(context.Background(), "someuser", rows)
+	if err != nil {
+		t.Fatalf("failed to import: %s", err)
+	}
+	if report.Results[0].Status != StatusAdded || report.Results[0].ReleaseID != 8138518 {
+		t.Errorf("first result got=%+v", report.Results[0])
+	}
+	if report.Results[1].Status != StatusUnmatched {
+		t.Errorf("second result got=%+v", report.Results[1])
+	}
+	if n := len(s.CollectionItems("someuser")); n != 1 {
+		t.Errorf("items got=%d; want=1", n)
+	}
+}
+
+func TestImportResume(t *testing.T) {
+	i, s := newImporter(t)
+	i.DryRun = false
+
+	rows, err := ReadRows(strings.NewReader(testCSV))
+	if err != nil {
+		t.Fatalf("failed to read rows: %s", err)
+	}
+	rows = append(rows, Row{Record: 5, ReleaseID: 2}, Row{Record: 6, ReleaseID: 3})
+
+	// Folders, fields, create folder, add, rating, two fields and one add leave no room for line 6.
+	s.RateLimit = 8
+	report, err := i.Import(context.Background(), "someuser", rows)
+	if !errors.Is(err, discogs.ErrTooManyRequests) {
+		t.Fatalf("error got=%v; want=%v", err, discogs.ErrTooManyRequests)
+	}
+	if n := len(s.CollectionItems("someuser")); n != 2 {
+		t.Fatalf("items got=%d; want=2", n)
+	}
+	last := report.Results[len(report.Results)-1]
+	if last.Record != 6 || last.Status != StatusFailed {
+		t.Errorf("last result got=%+v", last)
+	}
+
+	s.ResetRateLimit()
+	i.Resume = report
+	report, err = i.Import(context.Background(), "someuser", rows)
+	if err != nil {
+		t.Fatalf("failed to resume: %s", err)
+	}
+	if n := len(s.CollectionItems("someuser")); n != 3 {
+		t.Errorf("items got=%d; want=3", n)
+	}
+	if len(report.Results) != 5 {
+		t.Errorf("results got=%d; want=5", len(report.Results))
+	}
+}
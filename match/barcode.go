@@ -0,0 +1,87 @@
+package match
+
+import (
+	"strings"
+)
+
+// NormalizeBarcode returns the digits of a barcode as printed on a sleeve,
+// dropping spaces, dashes and any other separators.
+func NormalizeBarcode(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// CheckDigit returns the GTIN check digit of the digits of a barcode
+// without its check digit, such as the first 11 digits of a UPC-A.
+func CheckDigit(digits string) byte {
+	var sum int
+	for i := 0; i < len(digits); i++ {
+		d := int(digits[len(digits)-1-i] - '0')
+		// Weights alternate 3, 1, ... from the rightmost data digit.
+		if i%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// ValidBarcode reports whether code is an EAN-8, UPC-A, EAN-13 or GTIN-14
+// with a correct check digit.
+func ValidBarcode(code string) bool {
+	switch len(code) {
+	case 8, 12, 13, 14:
+	default:
+		return false
+	}
+	if NormalizeBarcode(code) != code {
+		return false
+	}
+	return CheckDigit(code[:len(code)-1]) == code[len(code)-1]
+}
+
+// BarcodeVariants returns the forms a barcode may be entered as on Discogs,
+// starting with the normalized code itself. A UPC-A is also an EAN-13 with a
+// leading zero and vice versa, and a code missing its check digit is
+// completed.
+func BarcodeVariants(s string) []string {
+	code := NormalizeBarcode(s)
+	if code == "" {
+		return nil
+	}
+
+	variants := []string{code}
+	add := func(v string) {
+		for _, existing := range variants {
+			if existing == v {
+				return
+			}
+		}
+		variants = append(variants, v)
+	}
+
+	switch len(code) {
+	case 11:
+		// UPC-A without its check digit.
+		upc := code + string(CheckDigit(code))
+		add(upc)
+		add("0" + upc)
+	case 12:
+		if ValidBarcode(code) {
+			add("0" + code)
+		} else {
+			// EAN-13 without its check digit.
+			add(code + string(CheckDigit(code)))
+		}
+	case 13:
+		if code[0] == '0' {
+			add(code[1:])
+		}
+	}
+	return variants
+}
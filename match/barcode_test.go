@@ -0,0 +1,40 @@
+package match
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBarcodeVariants(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"0 36000 29145 2", []string{"036000291452", "0036000291452"}},
+		{"0036000-291452", []string{"0036000291452", "036000291452"}},
+		{"03600029145", []string{"03600029145", "036000291452", "0036000291452"}},
+		{"400638133393", []string{"400638133393", "4006381333931"}},
+		{"4006381333931", []string{"4006381333931"}},
+		{"n/a", nil},
+	}
+	for _, tt := range tests {
+		if got := BarcodeVariants(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("BarcodeVariants(%q) got=%q; want=%q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestValidBarcode(t *testing.T) {
+	for code, want := range map[string]bool{
+		"036000291452":  true,
+		"036000291453":  false,
+		"4006381333931": true,
+		"96385074":      true,
+		"12345":         false,
+		"03600029145a":  false,
+	} {
+		if got := ValidBarcode(code); got != want {
+			t.Errorf("ValidBarcode(%q) got=%t; want=%t", code, got, want)
+		}
+	}
+}
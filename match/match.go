@@ -0,0 +1,289 @@
+// Package match finds the Discogs release matching a barcode or catalog number.
+//
+// Candidates are searched by every form of the barcode and by catalog number
+// and label, scored against country, format and year hints and verified
+// against the identifiers of the fetched releases:
+//
+//	m := match.New(client)
+//	candidates, err := m.Match(ctx, match.Query{Barcode: "4 607160 000235", Country: "Russia"})
+//	if err != nil {
+//		return err
+//	}
+//	if len(candidates) > 0 && candidates[0].Confidence > 0.9 {
+//		fmt.Println(candidates[0].ID)
+//	}
+package match
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ninnemana/go-discogs"
+)
+
+// Client is the part of the discogs client used by the matcher.
+type Client interface {
+	discogs.SearchService
+	discogs.DatabaseService
+}
+
+// Query describes a physical release to match.
+// At least one of Barcode and Catno is required; the other fields are hints.
+type Query struct {
+	Barcode string
+	Catno   string
+	// Label narrows the catalog number search.
+	Label   string
+	Country string
+	// Format is a format name or description, such as "Vinyl", "CD" or "LP".
+	Format string
+	Year   int
+}
+
+// Candidate is a release matching a query.
+type Candidate struct {
+	ID     int
+	Result discogs.Result
+	// Release is the fetched release, set for the best candidates up to Matcher.Verify.
+	Release *discogs.Release
+	// Verified reports whether the release was fetched and its barcode or
+	// catalog number equals the query.
+	Verified bool
+	// Confidence is the share of the query that the candidate matches, from 0 to 1.
+	Confidence float64
+}
+
+// Weights of the parts of a query in the confidence of a candidate.
+// A barcode or catalog number found by search but not verified counts half.
+const (
+	barcodeWeight = 0.6
+	catnoWeight   = 0.25
+	countryWeight = 0.1
+	yearWeight    = 0.1
+	formatWeight  = 0.05
+)
+
+// Matcher matches releases.
+type Matcher struct {
+	client Client
+
+	// Verify is the number of best candidates fetched to verify them (default is 5).
+	Verify int
+	// PerPage is the number of search results requested per search (default is 50).
+	PerPage int
+}
+
+// New returns a matcher using client.
+func New(client Client) *Matcher {
+	return &Matcher{
+		client:  client,
+		Verify:  5,
+		PerPage: 50,
+	}
+}
+
+// candidate is a candidate being scored.
+type candidate struct {
+	Candidate
+	barcode bool // found by barcode search
+	catno   bool // found by catalog number search
+}
+
+// Match returns the candidates for q, best first.
+func (m *Matcher) Match(ctx context.Context, q Query) ([]Candidate, error) {
+	barcodes := BarcodeVariants(q.Barcode)
+	if len(barcodes) == 0 && q.Catno == "" {
+		return nil, nil
+	}
+
+	var (
+		found []*candidate
+		byID  = make(map[int]*candidate)
+	)
+	search := func(req discogs.SearchRequest) ([]*candidate, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		req.Type = discogs.SearchTypeRelease
+		req.Page = 1
+		req.PerPage = m.PerPage
+		res, err := m.client.Search(req)
+		if err != nil {
+			return nil, err
+		}
+		var out []*candidate
+		for _, r := range res.Results {
+			c, ok := byID[r.ID]
+			if !ok {
+				c = &candidate{Candidate: Candidate{ID: r.ID, Result: r}}
+				byID[r.ID] = c
+				found = append(found, c)
+			}
+			out = append(out, c)
+		}
+		return out, nil
+	}
+
+	for _, code := range barcodes {
+		cs, err := search(discogs.SearchRequest{Barcode: code})
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range cs {
+			c.barcode = true
+		}
+	}
+	if q.Catno != "" {
+		cs, err := search(discogs.SearchRequest{Catno: q.Catno, Label: q.Label})
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range cs {
+			c.catno = true
+		}
+	}
+
+	for _, c := range found {
+		c.Confidence = score(q, barcodes, c)
+	}
+	rank(found)
+
+	for i := 0; i < len(found) && i < m.Verify; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		c := found[i]
+		release, err := m.client.Release(c.ID)
+		if err != nil {
+			return nil, err
+		}
+		c.Release = release
+		c.Confidence = score(q, barcodes, c)
+	}
+	rank(found)
+
+	out := make([]Candidate, len(found))
+	for i, c := range found {
+		out[i] = c.Candidate
+	}
+	return out, nil
+}
+
+func rank(cs []*candidate) {
+	sort.SliceStable(cs, func(i, j int) bool {
+		if cs[i].Confidence != cs[j].Confidence {
+			return cs[i].Confidence > cs[j].Confidence
+		}
+		return cs[i].ID < cs[j].ID
+	})
+}
+
+// score returns the confidence of c and sets c.Verified. Hints use the
+// fetched release if there is one and the search result otherwise.
+func score(q Query, barcodes []string, c *candidate) float64 {
+	var total, got float64
+	c.Verified = false
+
+	match := func(weight float64, hit, verified, contradicted bool) {
+		total += weight
+		switch {
+		case verified:
+			got += weight
+			c.Verified = true
+		case hit && !contradicted:
+			got += weight / 2
+		}
+	}
+
+	if len(barcodes) > 0 {
+		verified, contradicted := false, false
+		if c.Release != nil {
+			var codes []string
+			for _, id := range c.Release.Identifiers {
+				if id.Type == "Barcode" {
+					codes = append(codes, NormalizeBarcode(id.Value))
+				}
+			}
+			verified = anyEqual(codes, barcodes)
+			contradicted = !verified && len(codes) > 0
+		}
+		match(barcodeWeight, c.barcode, verified, contradicted)
+	}
+
+	if q.Catno != "" {
+		verified, contradicted := false, false
+		if c.Release != nil {
+			var catnos []string
+			for _, l := range c.Release.Labels {
+				catnos = append(catnos, normalizeCatno(l.Catno))
+			}
+			verified = anyEqual(catnos, []string{normalizeCatno(q.Catno)})
+			contradicted = !verified && len(catnos) > 0
+		}
+		match(catnoWeight, c.catno, verified, contradicted)
+	}
+
+	country, year, formats := c.Result.Country, c.Result.Year, c.Result.Format
+	if c.Release != nil {
+		country = c.Release.Country
+		year = strconv.Itoa(c.Release.Year)
+		formats = nil
+		for _, f := range c.Release.Formats {
+			formats = append(formats, f.Name)
+			formats = append(formats, f.Descriptions...)
+		}
+	}
+
+	if q.Country != "" {
+		total += countryWeight
+		if strings.EqualFold(country, q.Country) {
+			got += countryWeight
+		}
+	}
+	if q.Year != 0 {
+		total += yearWeight
+		if year == strconv.Itoa(q.Year) {
+			got += yearWeight
+		}
+	}
+	if q.Format != "" {
+		total += formatWeight
+		for _, f := range formats {
+			if strings.EqualFold(f, q.Format) {
+				got += formatWeight
+				break
+			}
+		}
+	}
+
+	if total == 0 {
+		return 0
+	}
+	return got / total
+}
+
+// normalizeCatno drops case, spaces and punctuation from a catalog number,
+// so that "MLR 007" equals "mlr-007".
+func normalizeCatno(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func anyEqual(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x != "" && x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
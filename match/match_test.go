@@ -0,0 +1,101 @@
+package match
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ninnemana/go-discogs"
+	"github.com/ninnemana/go-discogs/discogstest"
+)
+
+func newMatcher(t *testing.T) *Matcher {
+	t.Helper()
+	s := discogstest.NewTestServer(t)
+	s.AddRelease(discogs.Release{
+		ID:          1,
+		Title:       "US Pressing",
+		Country:     "US",
+		Year:        1999,
+		Formats:     []discogs.Format{{Name: "Vinyl", Descriptions: []string{"LP"}}},
+		Identifiers: []discogs.Identifier{{Type: "Barcode", Value: "036000291452"}},
+	})
+	s.AddRelease(discogs.Release{
+		ID:          2,
+		Title:       "UK Pressing",
+		Country:     "UK",
+		Year:        2001,
+		Formats:     []discogs.Format{{Name: "CD"}},
+		Identifiers: []discogs.Identifier{{Type: "Barcode", Value: "0036000291452"}},
+	})
+	s.AddRelease(discogs.Release{
+		ID:     3,
+		Title:  "Catalog Number",
+		Labels: []discogs.LabelSource{{Name: "Some Label", Catno: "ABC-1"}},
+	})
+	s.AddRelease(discogs.Release{
+		ID:     4,
+		Title:  "Other Catalog Number",
+		Labels: []discogs.LabelSource{{Name: "Some Label", Catno: "ABC-10"}},
+	})
+
+	return New(s.NewClient(t, "token"))
+}
+
+func TestMatchBarcode(t *testing.T) {
+	m := newMatcher(t)
+
+	candidates, err := m.Match(context.Background(), Query{Barcode: "0 36000 29145 2", Country: "us", Format: "Vinyl", Year: 1999})
+	if err != nil {
+		t.Fatalf("failed to match: %s", err)
+	}
+	if len(candidates) != 2 {
+		t.Fatalf("candidates got=%d; want=2", len(candidates))
+	}
+
+	best := candidates[0]
+	if best.ID != 1 || !best.Verified || best.Confidence != 1 || best.Release == nil {
+		t.Errorf("best candidate got=%+v", best)
+	}
+	if c := candidates[1]; c.ID != 2 || !c.Verified || c.Confidence >= best.Confidence {
+		t.Errorf("second candidate got=%+v", c)
+	}
+}
+
+func TestMatchCatno(t *testing.T) {
+	m := newMatcher(t)
+
+	candidates, err := m.Match(context.Background(), Query{Catno: "ABC-1", Label: "Some Label"})
+	if err != nil {
+		t.Fatalf("failed to match: %s", err)
+	}
+	if len(candidates) != 2 {
+		t.Fatalf("candidates got=%d; want=2", len(candidates))
+	}
+	if c := candidates[0]; c.ID != 3 || !c.Verified || c.Confidence != 1 {
+		t.Errorf("best candidate got=%+v", c)
+	}
+	// ABC-10 contains the query but its fetched catalog number contradicts it.
+	if c := candidates[1]; c.ID != 4 || c.Verified || c.Confidence != 0 {
+		t.Errorf("second candidate got=%+v", c)
+	}
+
+	m.Verify = 0
+	candidates, err = m.Match(context.Background(), Query{Catno: "ABC-1"})
+	if err != nil {
+		t.Fatalf("failed to match: %s", err)
+	}
+	for _, c := range candidates {
+		if c.Verified || c.Release != nil || c.Confidence != 0.5 {
+			t.Errorf("unverified candidate got=%+v", c)
+		}
+	}
+}
+
+func TestMatchEmpty(t *testing.T) {
+	m := newMatcher(t)
+
+	candidates, err := m.Match(context.Background(), Query{Country: "US"})
+	if err != nil || candidates != nil {
+		t.Errorf("got=%v, %v; want no candidates", candidates, err)
+	}
+}
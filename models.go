@@ -46,14 +46,16 @@ type Image struct {
 	URI150      string `json:"uri150"`
 }
 
-// Track ...
+// Track is an entry of a tracklist. Length and ParsedPosition parse
+// its duration and position; SubTracks is only set on index tracks.
 type Track struct {
 	Duration     string         `json:"duration"`
 	Position     string         `json:"position"`
 	Title        string         `json:"title"`
-	Type         string         `json:"type_"`
+	Type         TrackType      `json:"type_"`
 	Extraartists []ArtistSource `json:"extraartists,omitempty"`
 	Artists      []ArtistSource `json:"artists,omitempty"`
+	SubTracks    []Track        `json:"sub_tracks,omitempty"`
 }
 
 // LabelSource ...
@@ -78,6 +80,7 @@ type Format struct {
 	Descriptions []string `json:"descriptions"`
 	Name         string   `json:"name"`
 	Qty          string   `json:"qty"`
+	Text         string   `json:"text,omitempty"`
 }
 
 // Company ...
@@ -136,16 +139,32 @@ type URLsList struct {
 
 // Version ...
 type Version struct {
-	Catno       string `json:"catno"`
-	Country     string `json:"country"`
-	Format      string `json:"format"`
-	ID          int    `json:"id"`
-	Label       string `json:"label"`
-	Released    string `json:"released"`
-	ResourceURL string `json:"resource_url"`
-	Status      string `json:"status"`
-	Thumb       string `json:"thumb"`
-	Title       string `json:"title"`
+	Catno              string        `json:"catno"`
+	Country            string        `json:"country"`
+	Format             string        `json:"format"`
+	MajorFormats       []string      `json:"major_formats,omitempty"`
+	FormatDescriptions []string      `json:"format_descriptions,omitempty"`
+	ID                 int           `json:"id"`
+	Label              string        `json:"label"`
+	Released           string        `json:"released"`
+	ResourceURL        string        `json:"resource_url"`
+	Status             ReleaseStatus `json:"status"`
+	Thumb              string        `json:"thumb"`
+	Title              string        `json:"title"`
+	Stats              VersionStats  `json:"stats"`
+}
+
+// VersionStats counts the users having and wanting a version. User is
+// only set for authenticated requests and counts the requesting user.
+type VersionStats struct {
+	Community VersionStat `json:"community"`
+	User      VersionStat `json:"user"`
+}
+
+// VersionStat counts the users having a version in their collection and wantlist.
+type VersionStat struct {
+	InCollection int `json:"in_collection"`
+	InWantlist   int `json:"in_wantlist"`
 }
 
 // Member ...
@@ -156,6 +175,14 @@ type Member struct {
 	ResourceURL string `json:"resource_url"`
 }
 
+// Group is a group an artist is or was a member of.
+type Group struct {
+	Active      bool   `json:"active"`
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	ResourceURL string `json:"resource_url"`
+}
+
 // Alias ...
 type Alias struct {
 	ID          int    `json:"id"`
@@ -170,26 +197,33 @@ type Sublable struct {
 	Name        string `json:"name"`
 }
 
-// ReleaseSource ...
-type ReleaseSource struct {
-	Artist      string `json:"artist"`
-	Catno       string `json:"catno"`
-	Format      string `json:"format"`
+// LabelRef refers to a label, like the parent of a label.
+type LabelRef struct {
 	ID          int    `json:"id"`
+	Name        string `json:"name"`
 	ResourceURL string `json:"resource_url"`
-	Status      string `json:"status"`
-	Thumb       string `json:"thumb"`
-	Title       string `json:"title"`
-	Year        int    `json:"year"`
-	MainRelease int    `json:"main_release"`
-	Role        string `json:"role"`
-	Type        string `json:"type"`
+}
+
+// ReleaseSource ...
+type ReleaseSource struct {
+	Artist      string        `json:"artist"`
+	Catno       string        `json:"catno"`
+	Format      string        `json:"format"`
+	ID          int           `json:"id"`
+	ResourceURL string        `json:"resource_url"`
+	Status      ReleaseStatus `json:"status"`
+	Thumb       string        `json:"thumb"`
+	Title       string        `json:"title"`
+	Year        int           `json:"year"`
+	MainRelease int           `json:"main_release"`
+	Role        string        `json:"role"`
+	Type        string        `json:"type"`
 }
 
 // Pagination ...
 type Pagination struct {
-	Sort      string // year, title, format
-	SortOrder string // asc, desc
+	Sort      SortKey   // one of the sort keys of the endpoint
+	SortOrder SortOrder // asc, desc
 	Page      int
 	PerPage   int
 }
@@ -201,8 +235,12 @@ func (p *Pagination) params() url.Values {
 	}
 
 	params := url.Values{}
-	params.Set("sort", p.Sort)
-	params.Set("sort_order", p.SortOrder)
+	if p.Sort != "" {
+		params.Set("sort", string(p.Sort))
+	}
+	if p.SortOrder != "" {
+		params.Set("sort_order", string(p.SortOrder))
+	}
 	params.Set("page", strconv.Itoa(p.Page))
 	params.Set("per_page", strconv.Itoa(p.PerPage))
 	return params
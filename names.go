@@ -0,0 +1,80 @@
+package discogs
+
+import (
+	"regexp"
+	"strings"
+)
+
+// disambiguation matches the numeric suffix Discogs adds to artists sharing
+// a name, like the " (2)" of "Eminem (2)".
+var disambiguation = regexp.MustCompile(`\s+\(\d+\)$`)
+
+// CleanName strips the numeric disambiguation suffix and the trailing "*"
+// marking a name variation from an artist or label name.
+func CleanName(name string) string {
+	name = strings.TrimSpace(name)
+	name = strings.TrimSpace(strings.TrimSuffix(name, "*"))
+	return disambiguation.ReplaceAllString(name, "")
+}
+
+// SortName returns the name the way Discogs sorts it: cleaned, with a
+// leading "The" moved to the end, like "Beatles, The".
+func SortName(name string) string {
+	name = CleanName(name)
+	if len(name) > 4 && strings.EqualFold(name[:4], "The ") {
+		return name[4:] + ", " + name[:3]
+	}
+	return name
+}
+
+// PlainName returns the name credited on the release: the name variation
+// if there is one, otherwise the cleaned artist name.
+func (a ArtistSource) PlainName() string {
+	if a.Anv != "" {
+		return a.Anv
+	}
+	return CleanName(a.Name)
+}
+
+// DisplayName returns the name as discogs.com shows it: the cleaned artist
+// name, or the name variation followed by "*".
+func (a ArtistSource) DisplayName() string {
+	if a.Anv != "" {
+		return a.Anv + "*"
+	}
+	return CleanName(a.Name)
+}
+
+// SortName returns the canonical sort name of the artist, ignoring name variations.
+func (a ArtistSource) SortName() string {
+	return SortName(a.Name)
+}
+
+// DisplayArtists joins artists the way discogs.com credits them, like
+// "Eminem Feat. Eiy-Kyu*": each artist is followed by its Join string,
+// a missing or "," join separating artists with a comma.
+func DisplayArtists(artists []ArtistSource) string {
+	return joinArtists(artists, ArtistSource.DisplayName)
+}
+
+// PlainArtists joins artists like DisplayArtists, without marking name variations.
+func PlainArtists(artists []ArtistSource) string {
+	return joinArtists(artists, ArtistSource.PlainName)
+}
+
+func joinArtists(artists []ArtistSource, name func(ArtistSource) string) string {
+	var b strings.Builder
+	for i, a := range artists {
+		b.WriteString(name(a))
+		if i == len(artists)-1 {
+			break
+		}
+		switch join := strings.TrimSpace(a.Join); join {
+		case "", ",":
+			b.WriteString(", ")
+		default:
+			b.WriteString(" " + join + " ")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,65 @@
+package discogs
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCleanName(t *testing.T) {
+	tests := map[string]string{
+		"Eminem":        "Eminem",
+		"Proof (3)":     "Proof",
+		"Eiy-Kyu*":      "Eiy-Kyu",
+		"Three (2)*":    "Three",
+		"Area 51 (12)":  "Area 51",
+		"(1) Artist":    "(1) Artist",
+		"Artist (Live)": "Artist (Live)",
+	}
+	for in, want := range tests {
+		if got := CleanName(in); got != want {
+			t.Errorf("%q: got=%q; want=%q", in, got, want)
+		}
+	}
+}
+
+func TestSortName(t *testing.T) {
+	tests := map[string]string{
+		"The Beatles":     "Beatles, The",
+		"The Police (2)":  "Police, The",
+		"Theatre Of Hate": "Theatre Of Hate",
+		"The":             "The",
+	}
+	for in, want := range tests {
+		if got := SortName(in); got != want {
+			t.Errorf("%q: got=%q; want=%q", in, got, want)
+		}
+	}
+}
+
+func TestDisplayArtists(t *testing.T) {
+	var m Master
+	if err := json.Unmarshal([]byte(masterJson), &m); err != nil {
+		t.Fatalf("failed to decode master: %s", err)
+	}
+
+	featuring := m.Tracklist[5].Extraartists
+	featuring[0].Join = "&"
+	if got := DisplayArtists(featuring); got != "Three* & Denaun Porter" {
+		t.Errorf("display got=%q", got)
+	}
+	if got := PlainArtists(featuring); got != "Three & Denaun Porter" {
+		t.Errorf("plain got=%q", got)
+	}
+
+	artists := []ArtistSource{
+		{Name: "Eminem", Join: "Feat."},
+		{Name: "Proof (3)", Join: ","},
+		{Name: "Eye-Kyu", Anv: "Eiy-Kyu"},
+	}
+	if got := DisplayArtists(artists); got != "Eminem Feat. Proof, Eiy-Kyu*" {
+		t.Errorf("display got=%q", got)
+	}
+	if got := artists[1].SortName(); got != "Proof" {
+		t.Errorf("sort name got=%q", got)
+	}
+}
@@ -11,6 +11,10 @@ func WithCredentials(creds *oauth.Credentials) Option {
 			t.creds = creds
 		case *userService:
 			t.creds = creds
+		case *searchService:
+			t.creds = creds
+		case *databaseService:
+			t.creds = creds
 		}
 	}
 }
@@ -22,6 +26,10 @@ func WithClient(client *oauth.Client) Option {
 			t.oauthClient = client
 		case *userService:
 			t.oauthClient = client
+		case *searchService:
+			t.oauthClient = client
+		case *databaseService:
+			t.oauthClient = client
 		}
 	}
 }
@@ -1,8 +1,12 @@
 package discogs
 
 import (
+	"context"
 	"net/url"
 	"strconv"
+
+	"github.com/gomodule/oauth1/oauth"
+	"go.opencensus.io/trace"
 )
 
 // SearchService is an interface to work with search.
@@ -12,39 +16,48 @@ type SearchService interface {
 	// Authentication (as any user) is required.
 	// https://www.discogs.com/developers/#page:database,header:database-search
 	Search(req SearchRequest) (*Search, error)
+	// SearchContext is Search authenticated with the OAuth client and
+	// credentials given by options, falling back to the token without them.
+	// OAuth-authenticated results carry the user's UserData.
+	SearchContext(ctx context.Context, req SearchRequest, options ...Option) (*Search, error)
 }
 
 // searchService ...
 type searchService struct {
-	url string
+	client      *client
+	url         string
+	oauthClient *oauth.Client
+	creds       *oauth.Credentials
 }
 
-func newSearchService(url string) SearchService {
+func newSearchService(c *client, url string) SearchService {
 	return &searchService{
-		url: url,
+		client: c,
+		url:    url,
 	}
 }
 
 // SearchRequest describes search request
 type SearchRequest struct {
-	Q            string // search query
-	Type         string // one of release, master, artist, label
-	Title        string // search by combined “Artist Name - Release Title” title field
-	ReleaseTitle string // search release titles
-	Credit       string // search release credits
-	Artist       string // search artist names
-	Anv          string // search artist ANV
-	Label        string // search label names
-	Genre        string // search genres
-	Style        string // search styles
-	Country      string // search release country
-	Year         string // search release year
-	Format       string // search formats
-	Catno        string // search catalog number
-	Barcode      string // search barcodes
-	Track        string // search track titles
-	Submitter    string // search submitter username
-	Contributor  string // search contributor usernames
+	Q            string     // search query
+	Type         SearchType // one of release, master, artist, label
+	Title        string     // search by combined “Artist Name - Release Title” title field
+	ReleaseTitle string     // search release titles
+	Credit       string     // search release credits
+	Artist       string     // search artist names
+	Anv          string     // search artist ANV
+	Label        string     // search label names
+	Genre        string     // search genres
+	Style        string     // search styles
+	Country      string     // search release country
+	Year         string     // search release year or year range, like 1990-1999
+	Format       string     // search formats
+	Formats      []string   // search additional formats, all of which must match
+	Catno        string     // search catalog number
+	Barcode      string     // search barcodes
+	Track        string     // search track titles
+	Submitter    string     // search submitter username
+	Contributor  string     // search contributor usernames
 
 	Page    int
 	PerPage int
@@ -61,7 +74,7 @@ func (r *SearchRequest) params() url.Values {
 		params.Set("q", r.Q)
 	}
 	if r.Type != "" {
-		params.Set("type", r.Type)
+		params.Set("type", string(r.Type))
 	}
 	if r.Title != "" {
 		params.Set("title", r.Title)
@@ -96,6 +109,9 @@ func (r *SearchRequest) params() url.Values {
 	if r.Format != "" {
 		params.Set("format", r.Format)
 	}
+	for _, f := range r.Formats {
+		params.Add("format", f)
+	}
 	if r.Catno != "" {
 		params.Set("catno", r.Catno)
 	}
@@ -112,7 +128,9 @@ func (r *SearchRequest) params() url.Values {
 		params.Set("contributor", r.Contributor)
 	}
 
-	params.Set("page", strconv.Itoa(r.Page))
+	if r.Page != 0 {
+		params.Set("page", strconv.Itoa(r.Page))
+	}
 	if r.PerPage != 0 {
 		params.Set("per_page", strconv.Itoa(r.PerPage))
 	}
@@ -125,28 +143,75 @@ type Search struct {
 	Results    []Result `json:"results,omitempty"`
 }
 
-// Result describes a part of search result
+// Result describes a part of search result. Type tells which fields are set;
+// AsRelease, AsMaster, AsArtist and AsLabel return the typed result.
 type Result struct {
-	Style       []string  `json:"style,omitempty"`
-	Thumb       string    `json:"thumb,omitempty"`
-	CoverImage  string    `json:"cover_image,omitempty"`
-	Title       string    `json:"title,omitempty"`
-	Country     string    `json:"country,omitempty"`
-	Format      []string  `json:"format,omitempty"`
-	URI         string    `json:"uri,omitempty"`
-	Community   Community `json:"community,omitempty"`
-	Label       []string  `json:"label,omitempty"`
-	Catno       string    `json:"catno,omitempty"`
-	Year        string    `json:"year,omitempty"`
-	Genre       []string  `json:"genre,omitempty"`
-	ResourceURL string    `json:"resource_url,omitempty"`
-	Type        string    `json:"type,omitempty"`
-	ID          int       `json:"id,omitempty"`
-	MasterID    int       `json:"master_id,omitempty"`
+	Style       []string   `json:"style,omitempty"`
+	Thumb       string     `json:"thumb,omitempty"`
+	CoverImage  string     `json:"cover_image,omitempty"`
+	Title       string     `json:"title,omitempty"`
+	Country     string     `json:"country,omitempty"`
+	Format      []string   `json:"format,omitempty"`
+	URI         string     `json:"uri,omitempty"`
+	Community   Community  `json:"community,omitempty"`
+	Label       []string   `json:"label,omitempty"`
+	Catno       string     `json:"catno,omitempty"`
+	Barcode     []string   `json:"barcode,omitempty"`
+	Year        string     `json:"year,omitempty"`
+	Genre       []string   `json:"genre,omitempty"`
+	ResourceURL string     `json:"resource_url,omitempty"`
+	Type        SearchType `json:"type,omitempty"`
+	ID          int        `json:"id,omitempty"`
+	MasterID    int        `json:"master_id,omitempty"`
+	UserData    *UserData  `json:"user_data,omitempty"`
 }
 
 func (s *searchService) Search(req SearchRequest) (*Search, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
 	var search *Search
-	err := request(s.url, req.params(), &search)
+	err := s.client.request(s.url, req.params(), &search)
 	return search, err
 }
+
+func (s *searchService) SearchContext(ctx context.Context, req SearchRequest, options ...Option) (*Search, error) {
+	ctx, span := trace.StartSpan(ctx, "ninnemana.discogs.SearchContext")
+	defer span.End()
+
+	// options apply to this call only, the service is shared
+	sc := *s
+	for _, opts := range options {
+		opts(&sc)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	span.AddAttributes(
+		trace.StringAttribute("route", sc.url),
+		trace.BoolAttribute("oauth", sc.oauthClient != nil && sc.creds != nil),
+	)
+
+	var (
+		search Search
+		err    error
+	)
+	if sc.oauthClient != nil && sc.creds != nil {
+		err = sc.client.requestWithCreds(ctx, sc.url, sc.oauthClient, sc.creds, req.params(), &search)
+	} else {
+		err = sc.client.request(sc.url, req.params(), &search)
+	}
+	if err != nil {
+		span.SetStatus(trace.Status{
+			Code: trace.StatusCodeInternal,
+		})
+		span.AddAttributes(trace.StringAttribute("err", err.Error()))
+
+		return nil, err
+	}
+
+	return &search, nil
+}
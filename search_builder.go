@@ -0,0 +1,293 @@
+package discogs
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// yearPattern matches a year or a year range such as "1990-1999".
+var yearPattern = regexp.MustCompile(`^(\d{4})(?:-(\d{4}))?$`)
+
+// validYear reports whether s is a year or an ascending year range.
+func validYear(s string) bool {
+	m := yearPattern.FindStringSubmatch(s)
+	if m == nil {
+		return false
+	}
+	return m[2] == "" || m[1] <= m[2]
+}
+
+// SearchBuilder builds a validated SearchRequest:
+//
+//	req, err := discogs.NewSearchBuilder().
+//		Artist("reggaenauts").
+//		Type(discogs.SearchTypeRelease).
+//		Years(2010, 2019).
+//		Format("Vinyl", "LP").
+//		PerPage(50).
+//		Build()
+//
+// The first invalid value is reported by Build.
+type SearchBuilder struct {
+	req SearchRequest
+	err error
+}
+
+// NewSearchBuilder returns an empty search builder.
+func NewSearchBuilder() *SearchBuilder {
+	return &SearchBuilder{}
+}
+
+func (b *SearchBuilder) fail(err error) *SearchBuilder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+// Query sets the search query.
+func (b *SearchBuilder) Query(q string) *SearchBuilder {
+	b.req.Q = q
+	return b
+}
+
+// Type limits results to one entity type.
+func (b *SearchBuilder) Type(t SearchType) *SearchBuilder {
+	if !t.Valid() {
+		return b.fail(fmt.Errorf("%w %q", ErrInvalidSearchType, t))
+	}
+	b.req.Type = t
+	return b
+}
+
+// Title searches the combined "Artist Name - Release Title" title field.
+func (b *SearchBuilder) Title(title string) *SearchBuilder {
+	b.req.Title = title
+	return b
+}
+
+// ReleaseTitle searches release titles.
+func (b *SearchBuilder) ReleaseTitle(title string) *SearchBuilder {
+	b.req.ReleaseTitle = title
+	return b
+}
+
+// Credit searches release credits.
+func (b *SearchBuilder) Credit(credit string) *SearchBuilder {
+	b.req.Credit = credit
+	return b
+}
+
+// Artist searches artist names.
+func (b *SearchBuilder) Artist(artist string) *SearchBuilder {
+	b.req.Artist = artist
+	return b
+}
+
+// Anv searches artist name variations.
+func (b *SearchBuilder) Anv(anv string) *SearchBuilder {
+	b.req.Anv = anv
+	return b
+}
+
+// Label searches label names.
+func (b *SearchBuilder) Label(label string) *SearchBuilder {
+	b.req.Label = label
+	return b
+}
+
+// Genre searches genres.
+func (b *SearchBuilder) Genre(genre string) *SearchBuilder {
+	b.req.Genre = genre
+	return b
+}
+
+// Style searches styles.
+func (b *SearchBuilder) Style(style string) *SearchBuilder {
+	b.req.Style = style
+	return b
+}
+
+// Country searches release countries.
+func (b *SearchBuilder) Country(country string) *SearchBuilder {
+	b.req.Country = country
+	return b
+}
+
+// Year limits results to a release year.
+func (b *SearchBuilder) Year(year int) *SearchBuilder {
+	return b.YearString(strconv.Itoa(year))
+}
+
+// Years limits results to the release years from through to, inclusive.
+func (b *SearchBuilder) Years(from, to int) *SearchBuilder {
+	if from == to {
+		return b.Year(from)
+	}
+	return b.YearString(strconv.Itoa(from) + "-" + strconv.Itoa(to))
+}
+
+// YearString limits results to a year such as "1995" or a range such as "1990-1999".
+func (b *SearchBuilder) YearString(year string) *SearchBuilder {
+	if !validYear(year) {
+		return b.fail(fmt.Errorf("%w %q", ErrInvalidYear, year))
+	}
+	b.req.Year = year
+	return b
+}
+
+// Format adds formats results must have, such as "Vinyl" and "LP".
+func (b *SearchBuilder) Format(formats ...string) *SearchBuilder {
+	for _, f := range formats {
+		if f == "" {
+			continue
+		}
+		if b.req.Format == "" {
+			b.req.Format = f
+		} else {
+			b.req.Formats = append(b.req.Formats, f)
+		}
+	}
+	return b
+}
+
+// Catno searches catalog numbers.
+func (b *SearchBuilder) Catno(catno string) *SearchBuilder {
+	b.req.Catno = catno
+	return b
+}
+
+// Barcode searches barcodes.
+func (b *SearchBuilder) Barcode(barcode string) *SearchBuilder {
+	b.req.Barcode = barcode
+	return b
+}
+
+// Track searches track titles.
+func (b *SearchBuilder) Track(track string) *SearchBuilder {
+	b.req.Track = track
+	return b
+}
+
+// Submitter searches the username of the submitter.
+func (b *SearchBuilder) Submitter(username string) *SearchBuilder {
+	b.req.Submitter = username
+	return b
+}
+
+// Contributor searches the usernames of contributors.
+func (b *SearchBuilder) Contributor(username string) *SearchBuilder {
+	b.req.Contributor = username
+	return b
+}
+
+// Page sets the page number, starting at 1.
+func (b *SearchBuilder) Page(page int) *SearchBuilder {
+	if page < 1 {
+		return b.fail(fmt.Errorf("%w: page %d", ErrInvalidPagination, page))
+	}
+	b.req.Page = page
+	return b
+}
+
+// PerPage sets the number of results per page, from 1 to 100.
+func (b *SearchBuilder) PerPage(perPage int) *SearchBuilder {
+	if perPage < 1 || perPage > maxPerPage {
+		return b.fail(fmt.Errorf("%w: per page %d", ErrInvalidPagination, perPage))
+	}
+	b.req.PerPage = perPage
+	return b
+}
+
+// Build returns the request or the first invalid value.
+func (b *SearchBuilder) Build() (SearchRequest, error) {
+	if b.err != nil {
+		return SearchRequest{}, b.err
+	}
+	if err := b.req.Validate(); err != nil {
+		return SearchRequest{}, err
+	}
+	return b.req, nil
+}
+
+// ParseSearchURL returns the request matching a search on the Discogs website,
+// such as https://www.discogs.com/search/?q=nirvana&type=release&format_exact=Vinyl&decade=1990.
+// Website filters without an API counterpart, like sorting and layout, are ignored
+// and page sizes over 100 are reduced to 100.
+func ParseSearchURL(rawurl string) (SearchRequest, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return SearchRequest{}, err
+	}
+	host := strings.TrimPrefix(u.Host, "www.")
+	if host != "discogs.com" || strings.TrimSuffix(u.Path, "/") != "/search" {
+		return SearchRequest{}, fmt.Errorf("discogs: not a search URL: %s", rawurl)
+	}
+
+	b := NewSearchBuilder()
+	query := u.Query()
+	get := func(keys ...string) string {
+		for _, k := range keys {
+			if v := query.Get(k); v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+
+	b.Query(get("q"))
+	if t := get("type"); t != "all" {
+		b.Type(SearchType(t))
+	}
+	b.Title(get("title"))
+	b.ReleaseTitle(get("release_title"))
+	b.Credit(get("credit"))
+	b.Artist(get("artist"))
+	b.Anv(get("anv"))
+	b.Label(get("label_exact", "label"))
+	b.Genre(get("genre_exact", "genre"))
+	b.Style(get("style_exact", "style"))
+	b.Country(get("country_exact", "country"))
+	b.Catno(get("catno"))
+	b.Barcode(get("barcode"))
+	b.Track(get("track"))
+	b.Submitter(get("submitter"))
+	b.Contributor(get("contributor"))
+	b.Format(query["format_exact"]...)
+	b.Format(query["format"]...)
+
+	switch {
+	case get("year") != "":
+		b.YearString(get("year"))
+	case get("decade") != "":
+		decade, err := strconv.Atoi(get("decade"))
+		if err != nil {
+			b.fail(fmt.Errorf("%w: decade %q", ErrInvalidYear, get("decade")))
+			break
+		}
+		b.Years(decade, decade+9)
+	}
+
+	if v := get("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil {
+			return SearchRequest{}, fmt.Errorf("%w: page %q", ErrInvalidPagination, v)
+		}
+		b.Page(page)
+	}
+	if v := get("limit", "per_page"); v != "" {
+		perPage, err := strconv.Atoi(v)
+		if err != nil {
+			return SearchRequest{}, fmt.Errorf("%w: per page %q", ErrInvalidPagination, v)
+		}
+		if perPage > maxPerPage {
+			perPage = maxPerPage
+		}
+		b.PerPage(perPage)
+	}
+
+	return b.Build()
+}
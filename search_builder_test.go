@@ -0,0 +1,99 @@
+package discogs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSearchBuilder(t *testing.T) {
+	req, err := NewSearchBuilder().
+		Artist("reggaenauts").
+		Type(SearchTypeRelease).
+		Years(2010, 2019).
+		Format("Vinyl", "LP").
+		Page(2).
+		PerPage(50).
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build: %s", err)
+	}
+
+	params := req.params()
+	want := map[string][]string{
+		"artist":   {"reggaenauts"},
+		"type":     {"release"},
+		"year":     {"2010-2019"},
+		"format":   {"Vinyl", "LP"},
+		"page":     {"2"},
+		"per_page": {"50"},
+	}
+	for k, v := range want {
+		if !reflect.DeepEqual(params[k], v) {
+			t.Errorf("%s got=%q; want=%q", k, params[k], v)
+		}
+	}
+}
+
+func TestSearchBuilderInvalid(t *testing.T) {
+	tests := map[string]struct {
+		b    *SearchBuilder
+		want error
+	}{
+		"type":           {NewSearchBuilder().Type("releases"), ErrInvalidSearchType},
+		"year":           {NewSearchBuilder().YearString("90s"), ErrInvalidYear},
+		"reversed years": {NewSearchBuilder().Years(1999, 1990), ErrInvalidYear},
+		"per page":       {NewSearchBuilder().PerPage(0), ErrInvalidPagination},
+		"too many":       {NewSearchBuilder().PerPage(101), ErrInvalidPagination},
+		"page":           {NewSearchBuilder().Page(0), ErrInvalidPagination},
+		"first error":    {NewSearchBuilder().Type("x").PerPage(500), ErrInvalidSearchType},
+	}
+	for name, tt := range tests {
+		if _, err := tt.b.Build(); !errors.Is(err, tt.want) {
+			t.Errorf("%s: got=%v; want=%v", name, err, tt.want)
+		}
+	}
+}
+
+func TestSearchRequestParamsPage(t *testing.T) {
+	params := (&SearchRequest{Q: "infinite"}).params()
+	if _, ok := params["page"]; ok {
+		t.Errorf("page sent when unset: %v", params)
+	}
+}
+
+func TestParseSearchURL(t *testing.T) {
+	req, err := ParseSearchURL("https://www.discogs.com/search/?q=nirvana&type=release&format_exact=Vinyl&format_exact=LP&decade=1990&country_exact=US&genre_exact=Rock&sort=title&layout=sm&page=3&limit=250")
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	want := SearchRequest{
+		Q:       "nirvana",
+		Type:    SearchTypeRelease,
+		Format:  "Vinyl",
+		Formats: []string{"LP"},
+		Year:    "1990-1999",
+		Country: "US",
+		Genre:   "Rock",
+		Page:    3,
+		PerPage: 100,
+	}
+	if !reflect.DeepEqual(req, want) {
+		t.Errorf("got=%+v; want=%+v", req, want)
+	}
+
+	req, err = ParseSearchURL("https://www.discogs.com/search?q=eminem&type=all&year=1996")
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	if req.Type != "" || req.Year != "1996" || req.Q != "eminem" {
+		t.Errorf("got=%+v", req)
+	}
+
+	if _, err := ParseSearchURL("https://www.discogs.com/release/8138518"); err == nil {
+		t.Error("expected an error for a release URL")
+	}
+	if _, err := ParseSearchURL("https://www.discogs.com/search/?type=releases"); !errors.Is(err, ErrInvalidSearchType) {
+		t.Errorf("got=%v; want=%v", err, ErrInvalidSearchType)
+	}
+}
@@ -0,0 +1,225 @@
+package discogs
+
+import (
+	"fmt"
+)
+
+// UserData tells whether a search result is in the authenticated user's
+// collection or wantlist. It is only returned to authenticated searches.
+type UserData struct {
+	InCollection bool `json:"in_collection"`
+	InWantlist   bool `json:"in_wantlist"`
+}
+
+// BaseResult holds the fields shared by every type of search result.
+type BaseResult struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	Thumb       string    `json:"thumb,omitempty"`
+	CoverImage  string    `json:"cover_image,omitempty"`
+	URI         string    `json:"uri,omitempty"`
+	ResourceURL string    `json:"resource_url,omitempty"`
+	UserData    *UserData `json:"user_data,omitempty"`
+}
+
+// ReleaseResult is a release found by search.
+type ReleaseResult struct {
+	BaseResult
+	MasterID  int       `json:"master_id,omitempty"`
+	Country   string    `json:"country,omitempty"`
+	Year      string    `json:"year,omitempty"`
+	Format    []string  `json:"format,omitempty"`
+	Label     []string  `json:"label,omitempty"`
+	Catno     string    `json:"catno,omitempty"`
+	Barcode   []string  `json:"barcode,omitempty"`
+	Genre     []string  `json:"genre,omitempty"`
+	Style     []string  `json:"style,omitempty"`
+	Community Community `json:"community,omitempty"`
+}
+
+// MasterResult is a master release found by search.
+type MasterResult struct {
+	BaseResult
+	Country   string    `json:"country,omitempty"`
+	Year      string    `json:"year,omitempty"`
+	Format    []string  `json:"format,omitempty"`
+	Label     []string  `json:"label,omitempty"`
+	Catno     string    `json:"catno,omitempty"`
+	Genre     []string  `json:"genre,omitempty"`
+	Style     []string  `json:"style,omitempty"`
+	Community Community `json:"community,omitempty"`
+}
+
+// ArtistResult is an artist found by search; Title is the artist name.
+type ArtistResult struct {
+	BaseResult
+}
+
+// LabelResult is a label found by search; Title is the label name.
+type LabelResult struct {
+	BaseResult
+}
+
+func (r Result) base() BaseResult {
+	return BaseResult{
+		ID:          r.ID,
+		Title:       r.Title,
+		Thumb:       r.Thumb,
+		CoverImage:  r.CoverImage,
+		URI:         r.URI,
+		ResourceURL: r.ResourceURL,
+		UserData:    r.UserData,
+	}
+}
+
+// AsRelease returns the result as a release, if it is one.
+func (r Result) AsRelease() (*ReleaseResult, bool) {
+	if r.Type != SearchTypeRelease {
+		return nil, false
+	}
+	return &ReleaseResult{
+		BaseResult: r.base(),
+		MasterID:   r.MasterID,
+		Country:    r.Country,
+		Year:       r.Year,
+		Format:     r.Format,
+		Label:      r.Label,
+		Catno:      r.Catno,
+		Barcode:    r.Barcode,
+		Genre:      r.Genre,
+		Style:      r.Style,
+		Community:  r.Community,
+	}, true
+}
+
+// AsMaster returns the result as a master release, if it is one.
+func (r Result) AsMaster() (*MasterResult, bool) {
+	if r.Type != SearchTypeMaster {
+		return nil, false
+	}
+	return &MasterResult{
+		BaseResult: r.base(),
+		Country:    r.Country,
+		Year:       r.Year,
+		Format:     r.Format,
+		Label:      r.Label,
+		Catno:      r.Catno,
+		Genre:      r.Genre,
+		Style:      r.Style,
+		Community:  r.Community,
+	}, true
+}
+
+// AsArtist returns the result as an artist, if it is one.
+func (r Result) AsArtist() (*ArtistResult, bool) {
+	if r.Type != SearchTypeArtist {
+		return nil, false
+	}
+	return &ArtistResult{BaseResult: r.base()}, true
+}
+
+// AsLabel returns the result as a label, if it is one.
+func (r Result) AsLabel() (*LabelResult, bool) {
+	if r.Type != SearchTypeLabel {
+		return nil, false
+	}
+	return &LabelResult{BaseResult: r.base()}, true
+}
+
+// Releases returns the release results of the page.
+func (s *Search) Releases() []ReleaseResult {
+	var out []ReleaseResult
+	for _, r := range s.Results {
+		if rr, ok := r.AsRelease(); ok {
+			out = append(out, *rr)
+		}
+	}
+	return out
+}
+
+// Masters returns the master release results of the page.
+func (s *Search) Masters() []MasterResult {
+	var out []MasterResult
+	for _, r := range s.Results {
+		if mr, ok := r.AsMaster(); ok {
+			out = append(out, *mr)
+		}
+	}
+	return out
+}
+
+// Artists returns the artist results of the page.
+func (s *Search) Artists() []ArtistResult {
+	var out []ArtistResult
+	for _, r := range s.Results {
+		if ar, ok := r.AsArtist(); ok {
+			out = append(out, *ar)
+		}
+	}
+	return out
+}
+
+// Labels returns the label results of the page.
+func (s *Search) Labels() []LabelResult {
+	var out []LabelResult
+	for _, r := range s.Results {
+		if lr, ok := r.AsLabel(); ok {
+			out = append(out, *lr)
+		}
+	}
+	return out
+}
+
+// Resolve fetches the full release.
+func (r *ReleaseResult) Resolve(db DatabaseService) (*Release, error) {
+	return db.Release(r.ID)
+}
+
+// Resolve fetches the full master release.
+func (r *MasterResult) Resolve(db DatabaseService) (*Master, error) {
+	return db.Master(r.ID)
+}
+
+// Resolve fetches the full artist.
+func (r *ArtistResult) Resolve(db DatabaseService) (*Artist, error) {
+	return db.Artist(r.ID)
+}
+
+// Resolve fetches the full label.
+func (r *LabelResult) Resolve(db DatabaseService) (*Label, error) {
+	return db.Label(r.ID)
+}
+
+// Resolve fetches the full entity of a search result,
+// which is a *Release, *Master, *Artist or *Label depending on its type.
+// On error the entity is nil, not a nil pointer of the type.
+func Resolve(db DatabaseService, r Result) (interface{}, error) {
+	var (
+		v   interface{}
+		err error
+	)
+	switch r.Type {
+	case SearchTypeRelease:
+		var release *Release
+		release, err = db.Release(r.ID)
+		v = release
+	case SearchTypeMaster:
+		var master *Master
+		master, err = db.Master(r.ID)
+		v = master
+	case SearchTypeArtist:
+		var artist *Artist
+		artist, err = db.Artist(r.ID)
+		v = artist
+	case SearchTypeLabel:
+		var label *Label
+		label, err = db.Label(r.ID)
+		v = label
+	default:
+		err = fmt.Errorf("%w %q", ErrInvalidSearchType, r.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
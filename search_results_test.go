@@ -0,0 +1,93 @@
+package discogs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const mixedSearchJson = `{
+  "pagination": {"page": 1, "pages": 1, "per_page": 50, "items": 4, "urls": {}},
+  "results": [
+    {"type": "release", "id": 1, "master_id": 2, "title": "Reggaenauts - River Rock", "catno": "MLR-007", "format": ["Vinyl", "LP"], "year": "2016", "user_data": {"in_wantlist": true, "in_collection": false}},
+    {"type": "master", "id": 2, "master_id": 2, "title": "Reggaenauts - River Rock", "year": "2016"},
+    {"type": "artist", "id": 3, "title": "Reggaenauts"},
+    {"type": "label", "id": 4, "title": "Magnetic Loft Records"}
+  ]
+}`
+
+func SearchServer(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/database/search":
+		fmt.Fprint(w, mixedSearchJson)
+	case "/releases/1":
+		fmt.Fprint(w, `{"id": 1, "title": "River Rock"}`)
+	case "/masters/2":
+		fmt.Fprint(w, `{"id": 2, "title": "River Rock"}`)
+	case "/artists/3":
+		fmt.Fprint(w, `{"id": 3, "name": "Reggaenauts"}`)
+	case "/labels/4":
+		fmt.Fprint(w, `{"id": 4, "name": "Magnetic Loft Records"}`)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestSearchTypedResults(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(SearchServer))
+	defer ts.Close()
+
+	d := initDiscogsClient(t, &Options{URL: ts.URL})
+	search, err := d.Search(SearchRequest{Q: "reggaenauts"})
+	if err != nil {
+		t.Fatalf("failed to search: %s", err)
+	}
+
+	releases := search.Releases()
+	if len(releases) != 1 {
+		t.Fatalf("releases got=%d; want=1", len(releases))
+	}
+	r := releases[0]
+	if r.ID != 1 || r.MasterID != 2 || r.Catno != "MLR-007" || r.UserData == nil || !r.UserData.InWantlist {
+		t.Errorf("release result got=%+v", r)
+	}
+	if len(search.Masters()) != 1 || len(search.Artists()) != 1 || len(search.Labels()) != 1 {
+		t.Errorf("typed results got=%d masters, %d artists, %d labels; want one each",
+			len(search.Masters()), len(search.Artists()), len(search.Labels()))
+	}
+	if _, ok := search.Results[2].AsRelease(); ok {
+		t.Error("artist result converted to a release")
+	}
+
+	release, err := r.Resolve(d)
+	if err != nil || release.Title != "River Rock" {
+		t.Errorf("resolved release got=%+v, %v", release, err)
+	}
+	artist, err := search.Artists()[0].Resolve(d)
+	if err != nil || artist.Name != "Reggaenauts" {
+		t.Errorf("resolved artist got=%+v, %v", artist, err)
+	}
+
+	wants := []string{"*discogs.Release", "*discogs.Master", "*discogs.Artist", "*discogs.Label"}
+	for i, res := range search.Results {
+		v, err := Resolve(d, res)
+		if err != nil {
+			t.Fatalf("failed to resolve %s: %s", res.Type, err)
+		}
+		if got := fmt.Sprintf("%T", v); got != wants[i] {
+			t.Errorf("resolved type got=%s; want=%s", got, wants[i])
+		}
+	}
+
+	if _, err := Resolve(d, Result{Type: "releases"}); !errors.Is(err, ErrInvalidSearchType) {
+		t.Errorf("error got=%v; want=%v", err, ErrInvalidSearchType)
+	}
+	for _, typ := range []SearchType{SearchTypeRelease, SearchTypeMaster, SearchTypeArtist, SearchTypeLabel} {
+		v, err := Resolve(d, Result{Type: typ, ID: 99})
+		if !errors.Is(err, ErrNotFound) || v != nil {
+			t.Errorf("missing %s got=%#v, %v; want=nil, %v", typ, v, err, ErrNotFound)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package discogs
+
+import "context"
+
+// VersionCommunity is the community data of a version of a master release.
+type VersionCommunity struct {
+	Version
+	Have   int    `json:"have"`
+	Want   int    `json:"want"`
+	Rating Rating `json:"rating"`
+}
+
+// MasterCommunity is the community data of all versions of a master release.
+type MasterCommunity struct {
+	MasterID int `json:"master_id"`
+	// Have and Want are the sums over all versions.
+	Have int `json:"have"`
+	Want int `json:"want"`
+	// Rating averages all ratings of all versions.
+	Rating   Rating             `json:"rating"`
+	Versions []VersionCommunity `json:"versions"`
+	// MostWanted is the version with the most wants, nil without versions.
+	MostWanted *VersionCommunity `json:"most_wanted,omitempty"`
+	// MostCollected is the version with the most haves, nil without versions.
+	MostCollected *VersionCommunity `json:"most_collected,omitempty"`
+	// Truncated reports that the master has more versions than were fetched.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// AggregateMasterCommunity pages through the versions of a master release and
+// sums their community data. Every version costs one request for its release,
+// so at most limit versions are fetched, or all of them when limit is 0.
+// It stops with the context error once ctx is done.
+func AggregateMasterCommunity(ctx context.Context, db DatabaseService, masterID, limit int) (*MasterCommunity, error) {
+	mc := &MasterCommunity{MasterID: masterID}
+
+	var ratingSum float64
+pages:
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		versions, err := db.MasterVersions(masterID, &Pagination{Page: page, PerPage: maxPerPage})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range versions.Versions {
+			if limit > 0 && len(mc.Versions) >= limit {
+				mc.Truncated = true
+				break pages
+			}
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			release, err := db.Release(v.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			c := release.Community
+			mc.Versions = append(mc.Versions, VersionCommunity{
+				Version: v,
+				Have:    c.Have,
+				Want:    c.Want,
+				Rating:  c.Rating,
+			})
+			mc.Have += c.Have
+			mc.Want += c.Want
+			mc.Rating.Count += c.Rating.Count
+			ratingSum += float64(c.Rating.Average) * float64(c.Rating.Count)
+		}
+
+		if page >= versions.Pagination.Pages {
+			break
+		}
+	}
+
+	if mc.Rating.Count > 0 {
+		mc.Rating.Average = float32(ratingSum / float64(mc.Rating.Count))
+	}
+	for i := range mc.Versions {
+		v := &mc.Versions[i]
+		if mc.MostWanted == nil || v.Want > mc.MostWanted.Want {
+			mc.MostWanted = v
+		}
+		if mc.MostCollected == nil || v.Have > mc.MostCollected.Have {
+			mc.MostCollected = v
+		}
+	}
+	return mc, nil
+}
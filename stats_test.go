@@ -0,0 +1,77 @@
+package discogs_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ninnemana/go-discogs"
+	"github.com/ninnemana/go-discogs/discogstest"
+)
+
+func TestAggregateMasterCommunity(t *testing.T) {
+	s := discogstest.NewServer()
+	defer s.Close()
+
+	community := func(have, want int, avg float32, count int) discogs.Community {
+		return discogs.Community{Have: have, Want: want, Rating: discogs.Rating{Average: avg, Count: count}}
+	}
+	s.AddRelease(discogs.Release{ID: 1, Title: "Infinite", Community: community(100, 20, 4, 10)})
+	s.AddRelease(discogs.Release{ID: 2, Title: "Infinite", Community: community(50, 80, 5, 30)})
+	s.AddRelease(discogs.Release{ID: 3, Title: "Infinite", Community: community(10, 5, 0, 0)})
+	s.AddMaster(discogs.Master{ID: 10, Title: "Infinite", MainRelease: 1},
+		discogs.Version{ID: 1, Country: "US"},
+		discogs.Version{ID: 2, Country: "UK"},
+		discogs.Version{ID: 3, Country: "Europe"},
+	)
+
+	d, err := discogs.New(s.Options())
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+
+	stats, err := d.ReleaseStats(2)
+	if err != nil {
+		t.Fatalf("failed to get stats: %s", err)
+	}
+	if stats.NumHave != 50 || stats.NumWant != 80 {
+		t.Errorf("stats got=%+v; want 50 have, 80 want", stats)
+	}
+
+	mc, err := discogs.AggregateMasterCommunity(context.Background(), d, 10, 0)
+	if err != nil {
+		t.Fatalf("failed to aggregate: %s", err)
+	}
+	if mc.Have != 160 || mc.Want != 105 || len(mc.Versions) != 3 {
+		t.Errorf("got have=%d want=%d versions=%d; want 160, 105, 3", mc.Have, mc.Want, len(mc.Versions))
+	}
+	if mc.Rating.Count != 40 || mc.Rating.Average != 4.75 {
+		t.Errorf("rating got=%+v; want 4.75 over 40", mc.Rating)
+	}
+	if mc.MostWanted == nil || mc.MostWanted.ID != 2 || mc.MostWanted.Country != "UK" {
+		t.Errorf("most wanted got=%+v; want release 2", mc.MostWanted)
+	}
+	if mc.MostCollected == nil || mc.MostCollected.ID != 1 {
+		t.Errorf("most collected got=%+v; want release 1", mc.MostCollected)
+	}
+	if mc.Truncated {
+		t.Errorf("all versions reported as truncated")
+	}
+
+	mc, err = discogs.AggregateMasterCommunity(context.Background(), d, 10, 2)
+	if err != nil {
+		t.Fatalf("failed to aggregate: %s", err)
+	}
+	if len(mc.Versions) != 2 || !mc.Truncated || mc.Have != 150 {
+		t.Errorf("limited got versions=%d truncated=%t have=%d; want 2, true, 150", len(mc.Versions), mc.Truncated, mc.Have)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := discogs.AggregateMasterCommunity(ctx, d, 10, 0); err != context.Canceled {
+		t.Errorf("err got=%v; want=%v", err, context.Canceled)
+	}
+
+	if _, err := discogs.AggregateMasterCommunity(context.Background(), d, 11, 0); err != discogs.ErrNotFound {
+		t.Errorf("err got=%v; want=%v", err, discogs.ErrNotFound)
+	}
+}
@@ -0,0 +1,253 @@
+package discogs
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TrackType is the type of a tracklist entry.
+type TrackType string
+
+// Track types.
+const (
+	// TrackTypeTrack is a playable track.
+	TrackTypeTrack TrackType = "track"
+	// TrackTypeIndex groups sub tracks under a shared title, like the movements of a suite.
+	TrackTypeIndex TrackType = "index"
+	// TrackTypeHeading is a title between tracks, like "Bonus Tracks"; it has no position.
+	TrackTypeHeading TrackType = "heading"
+)
+
+// ParseDuration parses a track duration such as "4:05" or "1:02:03".
+// Minutes are not limited to 59 in the "m:ss" form, so "74:12" is valid.
+// An empty duration is unknown and returns 0 without an error.
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("%w %q", ErrInvalidDuration, s)
+	}
+
+	var d time.Duration
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 || p == "" {
+			return 0, fmt.Errorf("%w %q", ErrInvalidDuration, s)
+		}
+		// every part but the leading one is at most 59
+		if i > 0 && (n > 59 || len(p) != 2) {
+			return 0, fmt.Errorf("%w %q", ErrInvalidDuration, s)
+		}
+		d = d*60 + time.Duration(n)
+	}
+	return d * time.Second, nil
+}
+
+// Length returns the parsed duration of the track, or false
+// when the duration is missing or invalid.
+func (t Track) Length() (time.Duration, bool) {
+	d, err := ParseDuration(t.Duration)
+	if err != nil || d == 0 {
+		return 0, false
+	}
+	return d, true
+}
+
+// Position is a parsed track position. Discogs positions follow a few conventions:
+//
+//	A1, B2     side and track of a vinyl record or cassette; sides A and B are medium 1, C and D medium 2
+//	A, AA      a single track filling a side, or a double A side
+//	1, 12      track number on a single medium
+//	2-3        medium and track number
+//	CD1-04     medium format, medium and track number
+//	A3a, 1.2   a sub track of an index track
+type Position struct {
+	Format string // medium format, like "CD" in "CD1-04"
+	Medium int    // medium number, starting at 1
+	Side   string // side, like "A"; empty for media without sides
+	Index  int    // track number; 0 for a track filling a side
+	Sub    string // sub track, like "a" in "A3a" or "2" in "1.2"
+}
+
+var (
+	positionSide   = regexp.MustCompile(`^([A-Z]{1,2})(\d*)(?:\.(\w+)|([a-z]))?$`)
+	positionMedium = regexp.MustCompile(`^([A-Za-z]*)(\d*)-(\d+)(?:\.(\w+)|([a-z]))?$`)
+	positionNumber = regexp.MustCompile(`^(\d+)(?:\.(\w+)|([a-z]))?$`)
+)
+
+// ParsePosition parses a track position.
+// Headings have no position and return an error, as do unknown conventions.
+func ParsePosition(s string) (Position, error) {
+	s = strings.TrimSpace(s)
+	invalid := fmt.Errorf("%w %q", ErrInvalidPosition, s)
+
+	if m := positionMedium.FindStringSubmatch(s); m != nil {
+		if m[1] == "" && m[2] == "" {
+			return Position{}, invalid
+		}
+		p := Position{Format: m[1], Medium: 1, Sub: m[4] + m[5]}
+		if m[2] != "" {
+			p.Medium, _ = strconv.Atoi(m[2])
+		}
+		p.Index, _ = strconv.Atoi(m[3])
+		return p, nil
+	}
+
+	if m := positionSide.FindStringSubmatch(s); m != nil {
+		side := m[1]
+		if len(side) == 2 && side[0] != side[1] {
+			return Position{}, invalid
+		}
+		p := Position{
+			Medium: int(side[0]-'A')/2 + 1,
+			Side:   side,
+			Sub:    m[3] + m[4],
+		}
+		if m[2] != "" {
+			p.Index, _ = strconv.Atoi(m[2])
+		}
+		return p, nil
+	}
+
+	if m := positionNumber.FindStringSubmatch(s); m != nil {
+		p := Position{Medium: 1, Sub: m[2] + m[3]}
+		p.Index, _ = strconv.Atoi(m[1])
+		return p, nil
+	}
+
+	return Position{}, invalid
+}
+
+// ParsedPosition returns the parsed position of the track.
+func (t Track) ParsedPosition() (Position, error) {
+	return ParsePosition(t.Position)
+}
+
+// SideRuntime is the runtime of one side of a medium.
+type SideRuntime struct {
+	Side     string
+	Duration time.Duration
+	Tracks   int
+}
+
+// MediumRuntime is the runtime of one medium of a release.
+type MediumRuntime struct {
+	Format   string
+	Medium   int
+	Duration time.Duration
+	Tracks   int
+	Sides    []SideRuntime
+}
+
+// Runtime is the runtime of a tracklist.
+// Unknown counts the tracks without a duration, which make Duration a lower bound.
+type Runtime struct {
+	Duration time.Duration
+	Tracks   int
+	Unknown  int
+	Media    []MediumRuntime
+}
+
+// TracklistRuntime totals the durations of a tracklist per side, per medium and overall.
+// Headings are skipped. An index track counts its sub tracks, or itself when
+// none of them has a duration.
+// A track whose position does not parse belongs to the medium and side of the
+// track before it.
+func TracklistRuntime(tracks []Track) Runtime {
+	var (
+		rt      Runtime
+		current = Position{Medium: 1}
+	)
+	for _, t := range playable(tracks) {
+		if p, err := t.ParsedPosition(); err == nil {
+			current = p
+		}
+
+		m := medium(&rt, current)
+		d, ok := t.Length()
+		if !ok {
+			rt.Unknown++
+		}
+		rt.Duration += d
+		rt.Tracks++
+		m.Duration += d
+		m.Tracks++
+
+		if current.Side == "" {
+			continue
+		}
+		s := side(m, current.Side)
+		s.Duration += d
+		s.Tracks++
+	}
+	return rt
+}
+
+// playable returns the tracks of a tracklist counting towards its runtime.
+func playable(tracks []Track) []Track {
+	var out []Track
+	for _, t := range tracks {
+		switch t.Type {
+		case TrackTypeHeading:
+			continue
+		case TrackTypeIndex:
+			timed := false
+			for _, s := range t.SubTracks {
+				if _, ok := s.Length(); ok {
+					timed = true
+					break
+				}
+			}
+			if !timed {
+				out = append(out, t)
+				continue
+			}
+			for _, s := range t.SubTracks {
+				if s.Position == "" {
+					s.Position = t.Position
+				}
+				out = append(out, s)
+			}
+		default:
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func medium(rt *Runtime, p Position) *MediumRuntime {
+	for i := range rt.Media {
+		if rt.Media[i].Format == p.Format && rt.Media[i].Medium == p.Medium {
+			return &rt.Media[i]
+		}
+	}
+	rt.Media = append(rt.Media, MediumRuntime{Format: p.Format, Medium: p.Medium})
+	return &rt.Media[len(rt.Media)-1]
+}
+
+func side(m *MediumRuntime, name string) *SideRuntime {
+	for i := range m.Sides {
+		if m.Sides[i].Side == name {
+			return &m.Sides[i]
+		}
+	}
+	m.Sides = append(m.Sides, SideRuntime{Side: name})
+	return &m.Sides[len(m.Sides)-1]
+}
+
+// Runtime totals the durations of the release tracklist.
+func (r *Release) Runtime() Runtime {
+	return TracklistRuntime(r.Tracklist)
+}
+
+// Runtime totals the durations of the master tracklist.
+func (m *Master) Runtime() Runtime {
+	return TracklistRuntime(m.Tracklist)
+}
@@ -0,0 +1,111 @@
+package discogs
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want time.Duration
+		err  error
+	}{
+		"empty":   {"", 0, nil},
+		"minutes": {"4:05", 4*time.Minute + 5*time.Second, nil},
+		"long":    {"74:12", 74*time.Minute + 12*time.Second, nil},
+		"hours":   {"1:02:03", time.Hour + 2*time.Minute + 3*time.Second, nil},
+		"seconds": {"4:65", 0, ErrInvalidDuration},
+		"short":   {"4:5", 0, ErrInvalidDuration},
+		"bare":    {"245", 0, ErrInvalidDuration},
+		"text":    {"ca. 4 min", 0, ErrInvalidDuration},
+	}
+	for name, tt := range tests {
+		got, err := ParseDuration(tt.in)
+		if got != tt.want || !errors.Is(err, tt.err) {
+			t.Errorf("%s: got=%v, %v; want=%v, %v", name, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestParsePosition(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want Position
+		err  error
+	}{
+		"side":         {"A1", Position{Medium: 1, Side: "A", Index: 1}, nil},
+		"second disc":  {"D3", Position{Medium: 2, Side: "D", Index: 3}, nil},
+		"side long":    {"B", Position{Medium: 1, Side: "B"}, nil},
+		"double a":     {"AA", Position{Medium: 1, Side: "AA"}, nil},
+		"number":       {"12", Position{Medium: 1, Index: 12}, nil},
+		"medium":       {"2-3", Position{Medium: 2, Index: 3}, nil},
+		"format":       {"CD1-04", Position{Format: "CD", Medium: 1, Index: 4}, nil},
+		"format only":  {"DVD-5", Position{Format: "DVD", Medium: 1, Index: 5}, nil},
+		"sub":          {"A3a", Position{Medium: 1, Side: "A", Index: 3, Sub: "a"}, nil},
+		"dotted sub":   {"1.2", Position{Medium: 1, Index: 1, Sub: "2"}, nil},
+		"heading":      {"", Position{}, ErrInvalidPosition},
+		"mixed sides":  {"AB1", Position{}, ErrInvalidPosition},
+		"free text":    {"Video", Position{}, ErrInvalidPosition},
+		"missing left": {"-3", Position{}, ErrInvalidPosition},
+	}
+	for name, tt := range tests {
+		got, err := ParsePosition(tt.in)
+		if got != tt.want || !errors.Is(err, tt.err) {
+			t.Errorf("%s: got=%+v, %v; want=%+v, %v", name, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestTracklistRuntime(t *testing.T) {
+	var tracks []Track
+	if err := json.Unmarshal([]byte(`[
+		{"position": "A1", "type_": "track", "title": "One", "duration": "4:00"},
+		{"position": "A2", "type_": "track", "title": "Two", "duration": "3:30"},
+		{"position": "", "type_": "heading", "title": "Side Two"},
+		{"position": "B1", "type_": "index", "title": "Suite", "sub_tracks": [
+			{"position": "B1a", "type_": "track", "title": "Part I", "duration": "2:00"},
+			{"position": "B1b", "type_": "track", "title": "Part II", "duration": "2:30"}
+		]},
+		{"position": "C1", "type_": "track", "title": "Three", "duration": ""},
+		{"position": "Hidden", "type_": "track", "title": "Four", "duration": "1:00"}
+	]`), &tracks); err != nil {
+		t.Fatalf("failed to decode tracklist: %s", err)
+	}
+
+	got := TracklistRuntime(tracks)
+	want := Runtime{
+		Duration: 13 * time.Minute,
+		Tracks:   6,
+		Unknown:  1,
+		Media: []MediumRuntime{
+			{Medium: 1, Duration: 12 * time.Minute, Tracks: 4, Sides: []SideRuntime{
+				{Side: "A", Duration: 7*time.Minute + 30*time.Second, Tracks: 2},
+				{Side: "B", Duration: 4*time.Minute + 30*time.Second, Tracks: 2},
+			}},
+			{Medium: 2, Duration: time.Minute, Tracks: 2, Sides: []SideRuntime{
+				{Side: "C", Duration: time.Minute, Tracks: 2},
+			}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("runtime got=%+v; want=%+v", got, want)
+	}
+}
+
+func TestTracklistRuntimeIndexDuration(t *testing.T) {
+	tracks := []Track{
+		{Position: "1", Type: TrackTypeIndex, Duration: "10:00", SubTracks: []Track{
+			{Position: "1.1", Type: TrackTypeTrack},
+			{Position: "1.2", Type: TrackTypeTrack},
+		}},
+		{Position: "2", Type: TrackTypeTrack, Duration: "5:00"},
+	}
+	got := TracklistRuntime(tracks)
+	if got.Duration != 15*time.Minute || got.Tracks != 2 || got.Unknown != 0 {
+		t.Errorf("runtime got=%+v", got)
+	}
+}
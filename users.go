@@ -12,6 +12,7 @@ type UserService interface {
 }
 
 type userService struct {
+	client      *client
 	url         string
 	oauthClient *oauth.Client
 	creds       *oauth.Credentials
@@ -21,9 +22,10 @@ const (
 	oauthIdentityURI = "/oauth/identity"
 )
 
-func newUserService(url string) UserService {
+func newUserService(c *client, url string) UserService {
 	return &userService{
-		url: url,
+		client: c,
+		url:    url,
 	}
 }
 
@@ -50,7 +52,7 @@ func (u *userService) OAuthIdentity(ctx context.Context, options ...Option) (*Id
 
 	var id Identity
 
-	if err := requestWithCreds(
+	if err := u.client.requestWithCreds(
 		ctx,
 		route,
 		u.oauthClient,